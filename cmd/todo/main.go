@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"expvar"
 	"flag"
 	"fmt"
 	"log"
@@ -10,14 +11,20 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
+	"github.com/ernestngugi/todo/internal/controller"
 	"github.com/ernestngugi/todo/internal/db"
 	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
 	"github.com/ernestngugi/todo/internal/web/router"
 	"github.com/joho/godotenv"
 )
 
-const defaultPort = "8088"
+const (
+	defaultPort        = "8088"
+	trashPurgeInterval = time.Hour
+)
 
 func main() {
 
@@ -45,11 +52,84 @@ func main() {
 
 	redisManager := providers.NewRedisProvider(redisConfig)
 
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	memoryCacheConfig := &providers.MemoryCacheConfig{
+		MaxBytes:   64 << 20,
+		MaxEntries: 10000,
+	}
+
+	// CACHE_BACKEND picks where cached values live: redis (the default) is
+	// shared by every instance, memory only suits a single instance and
+	// tiered keeps hot values in memory in front of redis
+	var cacheBackend providers.Redis
+
+	switch backend := os.Getenv("CACHE_BACKEND"); backend {
+	case "", "redis":
+		cacheBackend = redisManager
+	case "memory":
+		cacheBackend = providers.NewMemoryCache(memoryCacheConfig)
+	case "tiered":
+		tieredCache := providers.NewTieredCache(&providers.TieredCacheConfig{
+			L1:         memoryCacheConfig,
+			L1TTL:      30 * time.Second,
+			SharedOnly: controller.SharedCacheKey,
+		}, redisManager)
+
+		go tieredCache.Run(backgroundCtx)
+
+		cacheBackend = tieredCache
+	default:
+		log.Fatalf("unknown CACHE_BACKEND %q", backend)
+	}
+
+	blobStore := providers.NewBlobStoreProvider(nil)
+
+	cacheConfig := &controller.CacheConfig{
+		DefaultTTL: time.Hour,
+		Jitter:     0.1,
+		TTLs: map[string]time.Duration{
+			controller.CacheFamilyTodo: 15 * time.Minute,
+			// pages retired by a generation bump linger until they expire
+			controller.CacheFamilyTodoList: 5 * time.Minute,
+		},
+	}
+
+	jwtConfig := &providers.JWTConfig{
+		AccessTokenTTL: 15 * time.Minute,
+	}
+
+	jwtProvider := providers.NewJWTProvider(jwtConfig)
+
 	appRouter := router.BuildRouter(
 		dB,
-		redisManager,
+		blobStore,
+		cacheConfig,
+		jwtProvider,
+		cacheBackend,
+	)
+
+	trashController := controller.NewTrashController(
+		nil,
+		repository.NewAttachmentRepository(),
+		blobStore,
+		repository.NewTodoRepository(),
 	)
 
+	go trashController.Run(backgroundCtx, dB, trashPurgeInterval)
+
+	// expvar counters such as cache_errors are served apart from the API so
+	// they are not exposed publicly
+	if metricsPort := os.Getenv("METRICS_PORT"); metricsPort != "" {
+		go func() {
+			err := http.ListenAndServe(":"+metricsPort, expvar.Handler())
+			if err != nil {
+				log.Printf("metrics server error: %v", err)
+			}
+		}()
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = defaultPort
@@ -69,6 +149,8 @@ func main() {
 
 		fmt.Println("shutting down")
 
+		stopBackground()
+
 		if err := server.Shutdown(context.Background()); err != nil {
 			log.Fatalf("Server shut down error: %v", err)
 		}
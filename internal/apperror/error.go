@@ -4,8 +4,17 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+
+	"github.com/lib/pq"
 )
 
+// uniqueViolation is the Postgres error code for a duplicate key.
+const uniqueViolation = "23505"
+
+// ErrConflict is wrapped by NewConflictError so callers can recognise a lost
+// update with errors.Is.
+var ErrConflict = errors.New("the resource was changed by another request, reload it and try again")
+
 type Error struct {
 	error          error //original error
 	httpStatusCode int
@@ -31,6 +40,10 @@ func (e *Error) Error() string {
 	return e.error.Error()
 }
 
+func (e *Error) Unwrap() error {
+	return e.error
+}
+
 func (e *Error) HttpStatusCode() int {
 	if e.httpStatusCode != 0 {
 		return e.httpStatusCode
@@ -56,3 +69,22 @@ func NewDatabaseError(err error) *Error {
 
 	return appError
 }
+
+// IsUniqueViolation reports whether err comes from a write that would have
+// duplicated a unique key.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == uniqueViolation
+}
+
+// NewConflictError reports that a row changed after it was read, so writing
+// it would overwrite someone else's change.
+func NewConflictError() *Error {
+	return Wrap(ErrConflict).SetHttpStatusCode(http.StatusConflict)
+}
+
+// NewPreconditionFailedError reports that an If-Match header no longer
+// matches the current version of a resource.
+func NewPreconditionFailedError(err error) *Error {
+	return Wrap(err).SetHttpStatusCode(http.StatusPreconditionFailed)
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/repository"
+)
+
+type Access int
+
+const (
+	AccessRead Access = iota
+	AccessWrite
+	AccessManage
+)
+
+type (
+	// AccessController decides whether a user may read or change a todo or
+	// workspace. Personal todos are only visible to their owner; workspace
+	// todos are visible to every member and writable by owners and editors.
+	AccessController interface {
+		AuthorizeTodo(ctx context.Context, dB db.DB, user *entities.User, todo *entities.Todo, access Access) error
+		AuthorizeWorkspace(ctx context.Context, dB db.DB, user *entities.User, workspaceID int64, access Access) (*entities.WorkspaceMember, error)
+	}
+
+	accessController struct {
+		workspaceRepository repository.WorkspaceRepository
+	}
+)
+
+func NewAccessController(
+	workspaceRepository repository.WorkspaceRepository,
+) AccessController {
+	return &accessController{
+		workspaceRepository: workspaceRepository,
+	}
+}
+
+func NewTestAccessController() *accessController {
+	return &accessController{
+		workspaceRepository: repository.NewWorkspaceRepository(),
+	}
+}
+
+func (s *accessController) AuthorizeTodo(ctx context.Context, dB db.DB, user *entities.User, todo *entities.Todo, access Access) error {
+
+	if todo.WorkspaceID == nil {
+
+		// hides the todo from everyone but its owner rather than revealing
+		// that it exists
+		if todo.OwnerID != user.ID {
+			return apperror.NewDatabaseError(sql.ErrNoRows)
+		}
+
+		return nil
+	}
+
+	member, err := s.workspaceMember(ctx, dB, user, *todo.WorkspaceID)
+	if err != nil {
+		return err
+	}
+
+	if access != AccessRead && !member.Role.CanWrite() {
+		return forbidden("you do not have permission to modify this todo")
+	}
+
+	return nil
+}
+
+func (s *accessController) AuthorizeWorkspace(ctx context.Context, dB db.DB, user *entities.User, workspaceID int64, access Access) (*entities.WorkspaceMember, error) {
+
+	member, err := s.workspaceMember(ctx, dB, user, workspaceID)
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	switch access {
+	case AccessWrite:
+		if !member.Role.CanWrite() {
+			return &entities.WorkspaceMember{}, forbidden("you do not have permission to modify this workspace")
+		}
+	case AccessManage:
+		if !member.Role.CanManage() {
+			return &entities.WorkspaceMember{}, forbidden("only workspace owners can manage this workspace")
+		}
+	}
+
+	return member, nil
+}
+
+// workspaceMember treats non-members as if the workspace did not exist.
+func (s *accessController) workspaceMember(ctx context.Context, dB db.DB, user *entities.User, workspaceID int64) (*entities.WorkspaceMember, error) {
+	return s.workspaceRepository.MemberByUser(ctx, dB, workspaceID, user.ID)
+}
+
+func forbidden(message string) error {
+	return apperror.Wrap(errors.New(message)).SetHttpStatusCode(http.StatusForbidden)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+)
+
+const (
+	APIKeyPrefix = "todo_"
+
+	apiKeyDisplayLength   = 12
+	apiKeySize            = 32
+	apiKeyUsageResolution = time.Minute
+)
+
+type (
+	APIKeyController interface {
+		APIKeys(ctx context.Context, dB db.DB) ([]*entities.APIKey, error)
+		AuthenticateAPIKey(ctx context.Context, dB db.DB, key string) (*entities.User, *entities.APIKey, error)
+		CreateAPIKey(ctx context.Context, dB db.DB, form *forms.CreateAPIKeyForm) (*entities.APIKey, error)
+		RevokeAPIKey(ctx context.Context, dB db.DB, apiKeyID int64) error
+	}
+
+	apiKeyController struct {
+		apiKeyRepository repository.APIKeyRepository
+		userRepository   repository.UserRepository
+	}
+)
+
+func NewAPIKeyController(
+	apiKeyRepository repository.APIKeyRepository,
+	userRepository repository.UserRepository,
+) APIKeyController {
+	return &apiKeyController{
+		apiKeyRepository: apiKeyRepository,
+		userRepository:   userRepository,
+	}
+}
+
+func NewTestAPIKeyController() *apiKeyController {
+	return &apiKeyController{
+		apiKeyRepository: repository.NewAPIKeyRepository(),
+		userRepository:   repository.NewUserRepository(),
+	}
+}
+
+func (s *apiKeyController) CreateAPIKey(ctx context.Context, dB db.DB, form *forms.CreateAPIKeyForm) (*entities.APIKey, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.APIKey{}, err
+	}
+
+	err = utils.ValidateSingleName(form.Name)
+	if err != nil {
+		return &entities.APIKey{}, err
+	}
+
+	scope := form.Scope
+	if scope == "" {
+		scope = entities.APIKeyScopeRead
+	}
+
+	if !scope.Valid() {
+		return &entities.APIKey{}, fmt.Errorf("invalid api key scope %v", scope)
+	}
+
+	secret, err := utils.GenerateToken(apiKeySize)
+	if err != nil {
+		return &entities.APIKey{}, err
+	}
+
+	key := APIKeyPrefix + secret
+
+	apiKey := &entities.APIKey{
+		UserID:  user.ID,
+		Name:    strings.TrimSpace(form.Name),
+		Prefix:  key[:apiKeyDisplayLength],
+		KeyHash: utils.HashToken(key),
+		Scope:   scope,
+	}
+
+	err = s.apiKeyRepository.Save(ctx, dB, apiKey)
+	if err != nil {
+		return &entities.APIKey{}, err
+	}
+
+	apiKey.Key = key
+
+	return apiKey, nil
+}
+
+func (s *apiKeyController) APIKeys(ctx context.Context, dB db.DB) ([]*entities.APIKey, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return []*entities.APIKey{}, err
+	}
+
+	return s.apiKeyRepository.APIKeysByUser(ctx, dB, user.ID)
+}
+
+func (s *apiKeyController) RevokeAPIKey(ctx context.Context, dB db.DB, apiKeyID int64) error {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := s.apiKeyRepository.APIKeyByID(ctx, dB, apiKeyID)
+	if err != nil {
+		return err
+	}
+
+	if apiKey.UserID != user.ID {
+		return apperror.NewDatabaseError(sql.ErrNoRows)
+	}
+
+	if !apiKey.Active() {
+		return fmt.Errorf("api key has already been revoked")
+	}
+
+	timeNow := time.Now()
+	apiKey.RevokedAt = &timeNow
+
+	return s.apiKeyRepository.Save(ctx, dB, apiKey)
+}
+
+func (s *apiKeyController) AuthenticateAPIKey(ctx context.Context, dB db.DB, key string) (*entities.User, *entities.APIKey, error) {
+
+	apiKey, err := s.apiKeyRepository.APIKeyByHash(ctx, dB, utils.HashToken(key))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entities.User{}, &entities.APIKey{}, s.invalidAPIKeyError()
+		}
+		return &entities.User{}, &entities.APIKey{}, err
+	}
+
+	if !apiKey.Active() {
+		return &entities.User{}, &entities.APIKey{}, s.invalidAPIKeyError()
+	}
+
+	user, err := s.userRepository.UserByID(ctx, dB, apiKey.UserID)
+	if err != nil {
+		return &entities.User{}, &entities.APIKey{}, err
+	}
+
+	timeNow := time.Now()
+
+	if apiKey.LastUsedAt == nil || timeNow.Sub(*apiKey.LastUsedAt) >= apiKeyUsageResolution {
+
+		apiKey.LastUsedAt = &timeNow
+
+		err = s.apiKeyRepository.UpdateLastUsed(ctx, dB, apiKey)
+		if err != nil {
+			return &entities.User{}, &entities.APIKey{}, err
+		}
+	}
+
+	return user, apiKey, nil
+}
+
+func (s *apiKeyController) invalidAPIKeyError() error {
+	return apperror.Wrap(errors.New("invalid api key")).SetHttpStatusCode(http.StatusUnauthorized)
+}
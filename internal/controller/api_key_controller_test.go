@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAPIKeyController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestAPIKeyController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		apiKeyController := NewTestAPIKeyController()
+
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
+		Convey("can create an api key", func() {
+
+			apiKey, err := apiKeyController.CreateAPIKey(ctx, dB, &forms.CreateAPIKeyForm{Name: "ci"})
+			So(err, ShouldBeNil)
+
+			So(apiKey.ID, ShouldNotBeZeroValue)
+			So(apiKey.UserID, ShouldEqual, user.ID)
+			So(apiKey.Key, ShouldStartWith, APIKeyPrefix)
+			So(apiKey.Key, ShouldStartWith, apiKey.Prefix)
+			So(apiKey.KeyHash, ShouldNotEqual, apiKey.Key)
+			So(apiKey.Scope, ShouldEqual, entities.APIKeyScopeRead)
+		})
+
+		Convey("cannot create an api key with an unknown scope", func() {
+
+			_, err := apiKeyController.CreateAPIKey(ctx, dB, &forms.CreateAPIKeyForm{Name: "ci", Scope: "admin"})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can list api keys without exposing the key", func() {
+
+			_, err := apiKeyController.CreateAPIKey(ctx, dB, &forms.CreateAPIKeyForm{Name: "ci"})
+			So(err, ShouldBeNil)
+
+			apiKeys, err := apiKeyController.APIKeys(ctx, dB)
+			So(err, ShouldBeNil)
+
+			So(len(apiKeys), ShouldEqual, 1)
+			So(apiKeys[0].Key, ShouldBeEmpty)
+		})
+
+		Convey("can authenticate with an api key and record its usage", func() {
+
+			apiKey, err := apiKeyController.CreateAPIKey(ctx, dB, &forms.CreateAPIKeyForm{
+				Name:  "ci",
+				Scope: entities.APIKeyScopeReadWrite,
+			})
+			So(err, ShouldBeNil)
+
+			authenticatedUser, authenticatedAPIKey, err := apiKeyController.AuthenticateAPIKey(ctx, dB, apiKey.Key)
+			So(err, ShouldBeNil)
+
+			So(authenticatedUser.ID, ShouldEqual, user.ID)
+			So(authenticatedAPIKey.Scope, ShouldEqual, entities.APIKeyScopeReadWrite)
+
+			foundAPIKey, err := apiKeyController.apiKeyRepository.APIKeyByID(ctx, dB, apiKey.ID)
+			So(err, ShouldBeNil)
+
+			So(foundAPIKey.LastUsedAt, ShouldNotBeNil)
+		})
+
+		Convey("cannot authenticate with a revoked api key", func() {
+
+			apiKey, err := apiKeyController.CreateAPIKey(ctx, dB, &forms.CreateAPIKeyForm{Name: "ci"})
+			So(err, ShouldBeNil)
+
+			err = apiKeyController.RevokeAPIKey(ctx, dB, apiKey.ID)
+			So(err, ShouldBeNil)
+
+			_, _, err = apiKeyController.AuthenticateAPIKey(ctx, dB, apiKey.Key)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "invalid api key")
+		})
+
+		Convey("cannot revoke another user's api key", func() {
+
+			apiKey, err := apiKeyController.CreateAPIKey(ctx, dB, &forms.CreateAPIKeyForm{Name: "ci"})
+			So(err, ShouldBeNil)
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			err = apiKeyController.RevokeAPIKey(contexthelper.WithUser(ctx, otherUser), dB, apiKey.ID)
+			So(err, ShouldNotBeNil)
+		})
+	}))
+}
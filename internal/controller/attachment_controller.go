@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+)
+
+const (
+	MaxAttachmentSize = 10 << 20
+
+	attachmentKeySize       = 16
+	defaultAttachmentName   = "attachment"
+	maxAttachmentNameLength = 255
+	contentTypeSniffLength  = 512
+)
+
+// allowedAttachmentTypes are matched against the sniffed content type, not the
+// one the client claims.
+var allowedAttachmentTypes = []string{
+	"application/pdf",
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+}
+
+type (
+	AttachmentController interface {
+		AttachmentContent(ctx context.Context, dB db.DB, todoID, attachmentID int64) (*entities.Attachment, io.ReadCloser, error)
+		Attachments(ctx context.Context, dB db.DB, todoID int64) ([]*entities.Attachment, error)
+		CreateAttachment(ctx context.Context, dB db.DB, todoID int64, fileName string, content io.Reader) (*entities.Attachment, error)
+		DeleteAttachment(ctx context.Context, dB db.DB, todoID, attachmentID int64) error
+	}
+
+	attachmentController struct {
+		accessController     AccessController
+		attachmentRepository repository.AttachmentRepository
+		blobStore            providers.BlobStore
+		todoRepository       repository.TodoRepository
+	}
+)
+
+func NewAttachmentController(
+	accessController AccessController,
+	attachmentRepository repository.AttachmentRepository,
+	blobStore providers.BlobStore,
+	todoRepository repository.TodoRepository,
+) AttachmentController {
+	return &attachmentController{
+		accessController:     accessController,
+		attachmentRepository: attachmentRepository,
+		blobStore:            blobStore,
+		todoRepository:       todoRepository,
+	}
+}
+
+func NewTestAttachmentController(
+	blobStore providers.BlobStore,
+) *attachmentController {
+	return &attachmentController{
+		accessController:     NewTestAccessController(),
+		attachmentRepository: repository.NewAttachmentRepository(),
+		blobStore:            blobStore,
+		todoRepository:       repository.NewTodoRepository(),
+	}
+}
+
+func (s *attachmentController) Attachments(ctx context.Context, dB db.DB, todoID int64) ([]*entities.Attachment, error) {
+
+	todo, err := s.todoForUser(ctx, dB, todoID, AccessRead)
+	if err != nil {
+		return []*entities.Attachment{}, err
+	}
+
+	return s.attachmentRepository.Attachments(ctx, dB, todo.ID)
+}
+
+// CreateAttachment streams the upload into the blob store, hashing it on the
+// way, and only records it once the whole file has been stored.
+func (s *attachmentController) CreateAttachment(ctx context.Context, dB db.DB, todoID int64, fileName string, content io.Reader) (*entities.Attachment, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Attachment{}, err
+	}
+
+	todo, err := s.todoForUser(ctx, dB, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Attachment{}, err
+	}
+
+	head := make([]byte, contentTypeSniffLength)
+
+	n, err := io.ReadFull(content, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return &entities.Attachment{}, err
+	}
+
+	head = head[:n]
+
+	if len(head) == 0 {
+		return &entities.Attachment{}, fmt.Errorf("attachment cannot be empty")
+	}
+
+	contentType := http.DetectContentType(head)
+	if !slices.Contains(allowedAttachmentTypes, contentType) {
+		return &entities.Attachment{}, apperror.Wrap(fmt.Errorf("unsupported attachment type %v, allowed types are %v", contentType, strings.Join(allowedAttachmentTypes, ", "))).SetHttpStatusCode(http.StatusUnsupportedMediaType)
+	}
+
+	token, err := utils.GenerateToken(attachmentKeySize)
+	if err != nil {
+		return &entities.Attachment{}, err
+	}
+
+	storageKey := fmt.Sprintf("todos/%d/%s", todo.ID, token)
+
+	checksum := sha256.New()
+
+	// one byte over the limit is enough to tell an oversized upload apart
+	reader := io.TeeReader(io.LimitReader(io.MultiReader(bytes.NewReader(head), content), MaxAttachmentSize+1), checksum)
+
+	size, err := s.blobStore.Put(storageKey, reader)
+	if err != nil {
+		return &entities.Attachment{}, err
+	}
+
+	if size > MaxAttachmentSize {
+		s.deleteBlob(storageKey)
+		return &entities.Attachment{}, apperror.Wrap(fmt.Errorf("attachment cannot be larger than %v MB", MaxAttachmentSize>>20)).SetHttpStatusCode(http.StatusRequestEntityTooLarge)
+	}
+
+	attachment := &entities.Attachment{
+		TodoID:      todo.ID,
+		UploadedBy:  user.ID,
+		FileName:    attachmentName(fileName),
+		ContentType: contentType,
+		Size:        size,
+		Checksum:    hex.EncodeToString(checksum.Sum(nil)),
+		StorageKey:  storageKey,
+	}
+
+	err = s.attachmentRepository.Save(ctx, dB, attachment)
+	if err != nil {
+		s.deleteBlob(storageKey)
+		return &entities.Attachment{}, err
+	}
+
+	return attachment, nil
+}
+
+// AttachmentContent returns the attachment with a reader over its content,
+// which the caller must close.
+func (s *attachmentController) AttachmentContent(ctx context.Context, dB db.DB, todoID, attachmentID int64) (*entities.Attachment, io.ReadCloser, error) {
+
+	attachment, err := s.attachmentForUser(ctx, dB, todoID, attachmentID, AccessRead)
+	if err != nil {
+		return &entities.Attachment{}, nil, err
+	}
+
+	content, err := s.blobStore.Get(attachment.StorageKey)
+	if err != nil {
+		if errors.Is(err, providers.ErrBlobNotFound) {
+			return &entities.Attachment{}, nil, apperror.NewDatabaseError(sql.ErrNoRows)
+		}
+		return &entities.Attachment{}, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+func (s *attachmentController) DeleteAttachment(ctx context.Context, dB db.DB, todoID, attachmentID int64) error {
+
+	attachment, err := s.attachmentForUser(ctx, dB, todoID, attachmentID, AccessWrite)
+	if err != nil {
+		return err
+	}
+
+	err = s.attachmentRepository.DeleteAttachment(ctx, dB, attachment.ID)
+	if err != nil {
+		return err
+	}
+
+	s.deleteBlob(attachment.StorageKey)
+
+	return nil
+}
+
+func (s *attachmentController) attachmentForUser(ctx context.Context, dB db.DB, todoID, attachmentID int64, access Access) (*entities.Attachment, error) {
+
+	todo, err := s.todoForUser(ctx, dB, todoID, access)
+	if err != nil {
+		return &entities.Attachment{}, err
+	}
+
+	attachment, err := s.attachmentRepository.AttachmentByID(ctx, dB, attachmentID)
+	if err != nil {
+		return &entities.Attachment{}, err
+	}
+
+	if attachment.TodoID != todo.ID {
+		return &entities.Attachment{}, apperror.NewDatabaseError(sql.ErrNoRows)
+	}
+
+	return attachment, nil
+}
+
+func (s *attachmentController) todoForUser(ctx context.Context, dB db.DB, todoID int64, access Access) (*entities.Todo, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	todo, err := s.todoRepository.TodoByID(ctx, dB, todoID)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	err = s.accessController.AuthorizeTodo(ctx, dB, user, todo, access)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+func (s *attachmentController) deleteBlob(storageKey string) {
+	deleteBlobs(s.blobStore, []string{storageKey})
+}
+
+// deleteBlobs is best effort: a blob left behind only wastes space, so a
+// failure is logged rather than returned.
+func deleteBlobs(blobStore providers.BlobStore, storageKeys []string) {
+	for _, storageKey := range storageKeys {
+		err := blobStore.Delete(storageKey)
+		if err != nil {
+			log.Printf("delete blob %v error: %v", storageKey, err)
+		}
+	}
+}
+
+// attachmentName keeps only the base name of an uploaded file, whichever
+// path separator the client used.
+func attachmentName(fileName string) string {
+
+	name := strings.TrimSpace(path.Base(strings.ReplaceAll(fileName, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return defaultAttachmentName
+	}
+
+	runes := []rune(name)
+	if len(runes) > maxAttachmentNameLength {
+		name = string(runes[:maxAttachmentNameLength])
+	}
+
+	return name
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/mocks"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// restoringTodoRepository restores a todo right after it is picked for
+// purging, as a concurrent restore could.
+type restoringTodoRepository struct {
+	repository.TodoRepository
+	todoID int64
+}
+
+func (r *restoringTodoRepository) PurgeableTodoIDs(ctx context.Context, operations db.SQLOperations, deletedBefore time.Time, limit int) ([]int64, error) {
+
+	todoIDs, err := r.TodoRepository.PurgeableTodoIDs(ctx, operations, deletedBefore, limit)
+	if err != nil {
+		return todoIDs, err
+	}
+
+	todo, err := r.DeletedTodoByID(ctx, operations, r.todoID)
+	if err != nil {
+		return todoIDs, err
+	}
+
+	return todoIDs, r.RestoreTodo(ctx, operations, todo)
+}
+
+func TestAttachmentController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestAttachmentController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		blobStore := mocks.NewMockBlobStore()
+
+		attachmentController := NewTestAttachmentController(blobStore)
+
+		todoController := NewTestTodoController(mocks.NewMockRedisProvider())
+
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
+		todo, err := repository.CreateTodo(ctx, dB, user)
+		So(err, ShouldBeNil)
+
+		pdf := []byte("%PDF-1.4\n" + strings.Repeat("0", 1024))
+
+		Convey("can upload and download an attachment", func() {
+
+			attachment, err := attachmentController.CreateAttachment(ctx, dB, todo.ID, `C:\Users\me\report.pdf`, bytes.NewReader(pdf))
+			So(err, ShouldBeNil)
+
+			checksum := sha256.Sum256(pdf)
+
+			So(attachment.FileName, ShouldEqual, "report.pdf")
+			So(attachment.ContentType, ShouldEqual, "application/pdf")
+			So(attachment.Size, ShouldEqual, len(pdf))
+			So(attachment.Checksum, ShouldEqual, hex.EncodeToString(checksum[:]))
+
+			_, content, err := attachmentController.AttachmentContent(ctx, dB, todo.ID, attachment.ID)
+			So(err, ShouldBeNil)
+
+			defer content.Close()
+
+			downloaded, err := io.ReadAll(content)
+			So(err, ShouldBeNil)
+
+			So(downloaded, ShouldResemble, pdf)
+		})
+
+		Convey("rejects unsupported file types", func() {
+
+			_, err := attachmentController.CreateAttachment(ctx, dB, todo.ID, "notes.txt", strings.NewReader("plain text"))
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusUnsupportedMediaType)
+		})
+
+		Convey("rejects files over the size limit", func() {
+
+			large := append([]byte("%PDF-1.4\n"), make([]byte, MaxAttachmentSize)...)
+
+			_, err := attachmentController.CreateAttachment(ctx, dB, todo.ID, "large.pdf", bytes.NewReader(large))
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusRequestEntityTooLarge)
+		})
+
+		Convey("keeps the blobs of a deleted todo until it is purged", func() {
+
+			attachment, err := attachmentController.CreateAttachment(ctx, dB, todo.ID, "report.pdf", bytes.NewReader(pdf))
+			So(err, ShouldBeNil)
+
+			err = todoController.DeleteTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(blobStore.Exists(attachment.StorageKey), ShouldBeTrue)
+
+			purged, err := NewTestTrashController(blobStore, 0).PurgeExpired(ctx, dB)
+			So(err, ShouldBeNil)
+
+			So(purged, ShouldEqual, 1)
+			So(blobStore.Exists(attachment.StorageKey), ShouldBeFalse)
+		})
+
+		Convey("keeps the blobs of a todo restored while it is being purged", func() {
+
+			attachment, err := attachmentController.CreateAttachment(ctx, dB, todo.ID, "report.pdf", bytes.NewReader(pdf))
+			So(err, ShouldBeNil)
+
+			err = todoController.DeleteTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			trashController := NewTestTrashController(blobStore, 0)
+			trashController.todoRepository = &restoringTodoRepository{
+				TodoRepository: repository.NewTodoRepository(),
+				todoID:         todo.ID,
+			}
+
+			purged, err := trashController.PurgeExpired(ctx, dB)
+			So(err, ShouldBeNil)
+
+			So(purged, ShouldEqual, 0)
+			So(blobStore.Exists(attachment.StorageKey), ShouldBeTrue)
+
+			_, err = todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+		})
+	}))
+}
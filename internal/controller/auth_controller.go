@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+)
+
+const (
+	refreshTokenSize = 32
+	refreshTokenTTL  = 30 * 24 * time.Hour
+	tokenTypeBearer  = "Bearer"
+)
+
+type (
+	AuthController interface {
+		AuthenticateToken(ctx context.Context, dB db.DB, token string) (*entities.User, error)
+		Login(ctx context.Context, dB db.DB, form *forms.LoginForm) (*entities.AuthTokens, error)
+		Logout(ctx context.Context, dB db.DB, form *forms.RefreshTokenForm) error
+		RefreshToken(ctx context.Context, dB db.DB, form *forms.RefreshTokenForm) (*entities.AuthTokens, error)
+		Signup(ctx context.Context, dB db.DB, form *forms.SignupForm) (*entities.AuthTokens, error)
+	}
+
+	authController struct {
+		jwtProvider            providers.JWT
+		refreshTokenRepository repository.RefreshTokenRepository
+		userRepository         repository.UserRepository
+	}
+)
+
+func NewAuthController(
+	jwtProvider providers.JWT,
+	refreshTokenRepository repository.RefreshTokenRepository,
+	userRepository repository.UserRepository,
+) AuthController {
+	return &authController{
+		jwtProvider:            jwtProvider,
+		refreshTokenRepository: refreshTokenRepository,
+		userRepository:         userRepository,
+	}
+}
+
+func NewTestAuthController(
+	jwtProvider providers.JWT,
+) *authController {
+	return &authController{
+		jwtProvider:            jwtProvider,
+		refreshTokenRepository: repository.NewRefreshTokenRepository(),
+		userRepository:         repository.NewUserRepository(),
+	}
+}
+
+func (s *authController) Signup(ctx context.Context, dB db.DB, form *forms.SignupForm) (*entities.AuthTokens, error) {
+
+	err := utils.ValidateEmail(form.Email)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	err = utils.ValidatePassword(form.Password)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	_, err = s.userRepository.UserByEmail(ctx, dB, form.Email)
+	if err == nil {
+		return &entities.AuthTokens{}, s.emailRegisteredError()
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return &entities.AuthTokens{}, err
+	}
+
+	passwordHash, err := utils.HashPassword(form.Password)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	user := &entities.User{
+		Email:        form.Email,
+		PasswordHash: passwordHash,
+	}
+
+	// a concurrent signup with the same email can get past the check above
+	err = s.userRepository.Save(ctx, dB, user)
+	if apperror.IsUniqueViolation(err) {
+		return &entities.AuthTokens{}, s.emailRegisteredError()
+	}
+
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	return s.issueTokens(ctx, dB, user)
+}
+
+func (s *authController) Login(ctx context.Context, dB db.DB, form *forms.LoginForm) (*entities.AuthTokens, error) {
+
+	user, err := s.userRepository.UserByEmail(ctx, dB, form.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entities.AuthTokens{}, s.invalidCredentialsError()
+		}
+		return &entities.AuthTokens{}, err
+	}
+
+	if !utils.CheckPassword(user.PasswordHash, form.Password) {
+		return &entities.AuthTokens{}, s.invalidCredentialsError()
+	}
+
+	return s.issueTokens(ctx, dB, user)
+}
+
+func (s *authController) RefreshToken(ctx context.Context, dB db.DB, form *forms.RefreshTokenForm) (*entities.AuthTokens, error) {
+
+	refreshToken, err := s.activeRefreshToken(ctx, dB, form.RefreshToken)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	user, err := s.userRepository.UserByID(ctx, dB, refreshToken.UserID)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	err = s.revokeRefreshToken(ctx, dB, refreshToken)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	return s.issueTokens(ctx, dB, user)
+}
+
+func (s *authController) Logout(ctx context.Context, dB db.DB, form *forms.RefreshTokenForm) error {
+
+	refreshToken, err := s.activeRefreshToken(ctx, dB, form.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	return s.revokeRefreshToken(ctx, dB, refreshToken)
+}
+
+func (s *authController) AuthenticateToken(ctx context.Context, dB db.DB, token string) (*entities.User, error) {
+
+	userID, err := s.jwtProvider.ValidateToken(token)
+	if err != nil {
+		return &entities.User{}, apperror.Wrap(err).SetHttpStatusCode(http.StatusUnauthorized)
+	}
+
+	user, err := s.userRepository.UserByID(ctx, dB, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entities.User{}, apperror.Wrap(errors.New("user not found")).SetHttpStatusCode(http.StatusUnauthorized)
+		}
+		return &entities.User{}, err
+	}
+
+	return user, nil
+}
+
+func (s *authController) activeRefreshToken(ctx context.Context, dB db.DB, token string) (*entities.RefreshToken, error) {
+
+	refreshToken, err := s.refreshTokenRepository.RefreshTokenByHash(ctx, dB, utils.HashToken(token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &entities.RefreshToken{}, s.invalidRefreshTokenError()
+		}
+		return &entities.RefreshToken{}, err
+	}
+
+	if !refreshToken.Active(time.Now()) {
+		return &entities.RefreshToken{}, s.invalidRefreshTokenError()
+	}
+
+	return refreshToken, nil
+}
+
+// revokeRefreshToken fails when another request revoked the token after it
+// was read, so a token presented twice at once is only honoured once.
+func (s *authController) revokeRefreshToken(ctx context.Context, dB db.DB, refreshToken *entities.RefreshToken) error {
+
+	revoked, err := s.refreshTokenRepository.Revoke(ctx, dB, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	if !revoked {
+		return s.invalidRefreshTokenError()
+	}
+
+	return nil
+}
+
+func (s *authController) issueTokens(ctx context.Context, dB db.DB, user *entities.User) (*entities.AuthTokens, error) {
+
+	accessToken, accessTokenExpiresAt, err := s.jwtProvider.GenerateToken(user.ID)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	token, err := utils.GenerateToken(refreshTokenSize)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	refreshToken := &entities.RefreshToken{
+		UserID:    user.ID,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(refreshTokenTTL),
+	}
+
+	err = s.refreshTokenRepository.Save(ctx, dB, refreshToken)
+	if err != nil {
+		return &entities.AuthTokens{}, err
+	}
+
+	authTokens := &entities.AuthTokens{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessTokenExpiresAt,
+		RefreshToken:          token,
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
+		TokenType:             tokenTypeBearer,
+		User:                  user,
+	}
+
+	return authTokens, nil
+}
+
+func (s *authController) emailRegisteredError() error {
+	return apperror.Wrap(errors.New("email already registered")).SetHttpStatusCode(http.StatusConflict)
+}
+
+func (s *authController) invalidCredentialsError() error {
+	return apperror.Wrap(errors.New("invalid email or password")).SetHttpStatusCode(http.StatusUnauthorized)
+}
+
+func (s *authController) invalidRefreshTokenError() error {
+	return apperror.Wrap(errors.New("invalid refresh token")).SetHttpStatusCode(http.StatusUnauthorized)
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/utils"
+	. "github.com/smartystreets/goconvey/convey"
+	"syreclabs.com/go/faker"
+)
+
+// unseenUserRepository finds no user by email.
+type unseenUserRepository struct {
+	repository.UserRepository
+}
+
+func (r *unseenUserRepository) UserByEmail(ctx context.Context, operations db.SQLOperations, email string) (*entities.User, error) {
+	return &entities.User{}, apperror.NewDatabaseError(sql.ErrNoRows)
+}
+
+func TestAuthController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestAuthController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		jwtProvider := providers.NewJWTWithSecret("test-secret", nil)
+
+		authController := NewTestAuthController(jwtProvider)
+
+		signupForm := &forms.SignupForm{
+			Email:    faker.Internet().Email(),
+			Password: "password123",
+		}
+
+		Convey("can sign up a user", func() {
+
+			authTokens, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			So(authTokens.AccessToken, ShouldNotBeEmpty)
+			So(authTokens.RefreshToken, ShouldNotBeEmpty)
+			So(authTokens.TokenType, ShouldEqual, "Bearer")
+			So(authTokens.User.ID, ShouldNotBeZeroValue)
+			So(authTokens.User.PasswordHash, ShouldNotEqual, signupForm.Password)
+		})
+
+		Convey("cannot sign up twice with the same email", func() {
+
+			_, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			_, err = authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "email already registered")
+		})
+
+		Convey("cannot sign up with a short password", func() {
+
+			signupForm.Password = "short"
+
+			_, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can login with valid credentials", func() {
+
+			_, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			authTokens, err := authController.Login(ctx, dB, &forms.LoginForm{
+				Email:    signupForm.Email,
+				Password: signupForm.Password,
+			})
+			So(err, ShouldBeNil)
+
+			So(authTokens.AccessToken, ShouldNotBeEmpty)
+		})
+
+		Convey("cannot login with an invalid password", func() {
+
+			_, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			_, err = authController.Login(ctx, dB, &forms.LoginForm{
+				Email:    signupForm.Email,
+				Password: "wrong-password",
+			})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "invalid email or password")
+		})
+
+		Convey("can authenticate an access token", func() {
+
+			authTokens, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			user, err := authController.AuthenticateToken(ctx, dB, authTokens.AccessToken)
+			So(err, ShouldBeNil)
+
+			So(user.ID, ShouldEqual, authTokens.User.ID)
+		})
+
+		Convey("cannot authenticate a token signed with another secret", func() {
+
+			authTokens, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			otherAuthController := NewTestAuthController(providers.NewJWTWithSecret("other-secret", nil))
+
+			_, err = otherAuthController.AuthenticateToken(ctx, dB, authTokens.AccessToken)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can rotate a refresh token", func() {
+
+			authTokens, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			refreshedTokens, err := authController.RefreshToken(ctx, dB, &forms.RefreshTokenForm{
+				RefreshToken: authTokens.RefreshToken,
+			})
+			So(err, ShouldBeNil)
+
+			So(refreshedTokens.RefreshToken, ShouldNotEqual, authTokens.RefreshToken)
+
+			_, err = authController.RefreshToken(ctx, dB, &forms.RefreshTokenForm{
+				RefreshToken: authTokens.RefreshToken,
+			})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "invalid refresh token")
+		})
+
+		Convey("cannot refresh after logout", func() {
+
+			authTokens, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			form := &forms.RefreshTokenForm{
+				RefreshToken: authTokens.RefreshToken,
+			}
+
+			err = authController.Logout(ctx, dB, form)
+			So(err, ShouldBeNil)
+
+			_, err = authController.RefreshToken(ctx, dB, form)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("only honours a refresh token once when it is presented concurrently", func() {
+
+			authTokens, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			refreshTokenRepository := repository.NewRefreshTokenRepository()
+
+			// both requests read the token while it is still active
+			refreshToken, err := refreshTokenRepository.RefreshTokenByHash(ctx, dB, utils.HashToken(authTokens.RefreshToken))
+			So(err, ShouldBeNil)
+
+			sameRefreshToken, err := refreshTokenRepository.RefreshTokenByHash(ctx, dB, utils.HashToken(authTokens.RefreshToken))
+			So(err, ShouldBeNil)
+
+			revoked, err := refreshTokenRepository.Revoke(ctx, dB, refreshToken)
+			So(err, ShouldBeNil)
+			So(revoked, ShouldBeTrue)
+
+			revoked, err = refreshTokenRepository.Revoke(ctx, dB, sameRefreshToken)
+			So(err, ShouldBeNil)
+			So(revoked, ShouldBeFalse)
+		})
+
+		Convey("reports a signup that lost the race for its email as a conflict", func() {
+
+			_, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			// the duplicate check misses the user, as it does when both
+			// signups run it before either inserts
+			racingAuthController := NewTestAuthController(jwtProvider)
+			racingAuthController.userRepository = &unseenUserRepository{UserRepository: repository.NewUserRepository()}
+
+			_, err = racingAuthController.Signup(ctx, dB, signupForm)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "email already registered")
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusConflict)
+		})
+	}))
+}
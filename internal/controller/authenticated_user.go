@@ -0,0 +1,21 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+)
+
+func authenticatedUser(ctx context.Context) (*entities.User, error) {
+
+	user := contexthelper.User(ctx)
+	if user == nil || user.IsNew() {
+		return &entities.User{}, apperror.Wrap(errors.New("user not authenticated")).SetHttpStatusCode(http.StatusUnauthorized)
+	}
+
+	return user, nil
+}
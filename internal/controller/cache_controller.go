@@ -2,36 +2,106 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"time"
 
 	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/utils"
+	"github.com/gomodule/redigo/redis"
+)
+
+const (
+	CacheFamilyTodo     = "todo"
+	CacheFamilyTodoList = "todos"
+
+	// counters kept with Increment are named "<family>:generation:<name>"
+	cacheCounterInfix     = ":generation:"
+	cacheLockPollInterval = 25 * time.Millisecond
+	cacheLockSuffix       = ":lock"
+	cacheLockTokenSize    = 16
+	cacheLockTTL          = 2 * time.Second
+	defaultCacheJitter    = 0.1
+	defaultCacheTTL       = time.Hour
 )
 
 type (
+	// CacheConfig sets how long cached values live. Keys are grouped into
+	// families by the part before their first colon, so "todo:todo-key:1"
+	// belongs to the "todo" family.
+	CacheConfig struct {
+		DefaultTTL time.Duration
+		// Jitter moves each expiry by up to this fraction of its TTL either
+		// way so values cached together do not all expire together.
+		Jitter float64
+		TTLs   map[string]time.Duration
+	}
+
 	CacheController interface {
 		CacheValue(key string, value any) error
+		Counter(key string) (int64, error)
 		Exists(key string) (bool, error)
 		GetCachedValue(key string, result any) error
+		GetOrLoad(key string, result any, load func() (any, error)) error
+		Increment(key string) (int64, error)
 		RemoveFromCache(key string) error
 	}
 
 	cacheController struct {
+		defaultTTL    time.Duration
+		jitter        float64
+		loads         utils.SingleFlight[[]byte]
 		redisProvider providers.Redis
+		ttls          map[string]time.Duration
 	}
 )
 
+// SharedCacheKey reports whether key is a load lock or a counter. They change
+// on every write, so a TieredCache keeps them in the shared cache only.
+func SharedCacheKey(key string) bool {
+	return strings.HasSuffix(key, cacheLockSuffix) || strings.Contains(key, cacheCounterInfix)
+}
+
 func NewCacheController(
+	config *CacheConfig,
 	redisProvider providers.Redis,
 ) CacheController {
-	return &cacheController{
+
+	cacheController := &cacheController{
+		defaultTTL:    defaultCacheTTL,
+		jitter:        defaultCacheJitter,
 		redisProvider: redisProvider,
+		ttls:          make(map[string]time.Duration),
+	}
+
+	if config != nil {
+
+		if config.DefaultTTL > 0 {
+			cacheController.defaultTTL = config.DefaultTTL
+		}
+
+		if config.Jitter > 0 {
+			cacheController.jitter = min(config.Jitter, 1)
+		}
+
+		for family, ttl := range config.TTLs {
+			cacheController.ttls[family] = ttl
+		}
 	}
+
+	return cacheController
 }
 
 func NewTestCacheController(
 	redisProvider providers.Redis,
 ) *cacheController {
 	return &cacheController{
+		defaultTTL:    defaultCacheTTL,
+		jitter:        defaultCacheJitter,
 		redisProvider: redisProvider,
+		ttls:          make(map[string]time.Duration),
 	}
 }
 
@@ -45,7 +115,12 @@ func (s *cacheController) CacheValue(
 		return err
 	}
 
-	_, err = s.redisProvider.Set(key, cacheData)
+	err = s.cancelLoad(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.redisProvider.SetWithTTL(key, cacheData, s.ttl(key))
 	if err != nil {
 		return err
 	}
@@ -53,6 +128,20 @@ func (s *cacheController) CacheValue(
 	return nil
 }
 
+// Counter reads a counter kept with Increment, which is zero until it is
+// first incremented.
+func (s *cacheController) Counter(
+	key string,
+) (int64, error) {
+
+	counter, err := redis.Int64(s.redisProvider.Get(key))
+	if errors.Is(err, redis.ErrNil) {
+		return 0, nil
+	}
+
+	return counter, err
+}
+
 func (s *cacheController) Exists(
 	key string,
 ) (bool, error) {
@@ -88,14 +177,199 @@ func (s *cacheController) GetCachedValue(
 	return nil
 }
 
+// GetOrLoad reads key into result, calling load on a miss and caching what it
+// returns. Misses for the same key share one load within this process, and a
+// short Redis lock makes other instances wait for that load instead of
+// repeating it. A value is not cached when its key was written to while it
+// was loading. When Redis fails the value is loaded without the cache; only
+// errors from load are returned.
+func (s *cacheController) GetOrLoad(
+	key string,
+	result any,
+	load func() (any, error),
+) error {
+
+	data, err := s.cachedData(key)
+	if err != nil {
+		logCacheError("get", err)
+	}
+
+	if data == nil {
+
+		data, err = s.loads.Do(key, func() ([]byte, error) {
+			return s.loadWithLock(key, load)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(data, result)
+}
+
+// Increment adds one to the counter at key. Counters do not expire, so they
+// can version other cached values.
+func (s *cacheController) Increment(
+	key string,
+) (int64, error) {
+	return s.redisProvider.Incr(key)
+}
+
 func (s *cacheController) RemoveFromCache(
 	key string,
 ) error {
 
-	err := s.redisProvider.Del(key)
+	err := s.cancelLoad(key)
+	if err != nil {
+		return err
+	}
+
+	err = s.redisProvider.Del(key)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// ttl picks the TTL of the key's family and spreads it by the jitter.
+func (s *cacheController) ttl(
+	key string,
+) time.Duration {
+
+	family, _, _ := strings.Cut(key, ":")
+
+	ttl, ok := s.ttls[family]
+	if !ok || ttl <= 0 {
+		ttl = s.defaultTTL
+	}
+
+	if s.jitter <= 0 {
+		return ttl
+	}
+
+	spread := time.Duration(float64(ttl) * s.jitter * (2*rand.Float64() - 1))
+
+	return ttl + spread
+}
+
+// cachedData returns nil when key is not cached.
+func (s *cacheController) cachedData(
+	key string,
+) ([]byte, error) {
+
+	payload, err := s.redisProvider.Get(key)
+	if errors.Is(err, redis.ErrNil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, _ := payload.([]byte)
+
+	return data, nil
+}
+
+// cancelLoad takes the lock away from a load of key that is in flight, so
+// the value it read before a write is not cached after it.
+func (s *cacheController) cancelLoad(
+	key string,
+) error {
+	return s.redisProvider.Del(key + cacheLockSuffix)
+}
+
+// loadWithLock loads the value under a lock holding a token of its own, and
+// only caches it while the lock is still held: a write to key in the meantime
+// takes the lock away, and so does its expiry.
+func (s *cacheController) loadWithLock(
+	key string,
+	load func() (any, error),
+) ([]byte, error) {
+
+	lockKey := key + cacheLockSuffix
+
+	token, err := utils.GenerateToken(cacheLockTokenSize)
+	if err != nil {
+		return nil, err
+	}
+
+	locked, err := s.redisProvider.SetNX(lockKey, token, cacheLockTTL)
+	if err != nil {
+		logCacheError("lock", err)
+	}
+
+	// another instance is loading the value; wait for it to be cached, or
+	// take over the lock once that load gives it up
+	for deadline := time.Now().Add(cacheLockTTL); !locked && err == nil && time.Now().Before(deadline); {
+
+		time.Sleep(cacheLockPollInterval)
+
+		var data []byte
+
+		data, err = s.cachedData(key)
+		if err != nil {
+			logCacheError("get", err)
+			break
+		}
+
+		if data != nil {
+			return data, nil
+		}
+
+		locked, err = s.redisProvider.SetNX(lockKey, token, cacheLockTTL)
+		if err != nil {
+			logCacheError("lock", err)
+		}
+	}
+
+	if locked {
+
+		defer func() {
+			_, err := s.redisProvider.Unlock(lockKey, token)
+			logCacheError("unlock", err)
+		}()
+
+		// the value may have been cached just before the lock was taken
+		data, err := s.cachedData(key)
+		if err != nil {
+			logCacheError("get", err)
+		}
+
+		if data != nil {
+			return data, nil
+		}
+	}
+
+	value, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if locked {
+		_, err = s.redisProvider.SetWithTTLIfLocked(key, data, s.ttl(key), lockKey, token)
+		logCacheError("set", err)
+	}
+
+	return data, nil
+}
+
+// logCacheError records a cache failure that is not worth failing a request
+// for: reads fall back to the database, and values that could not be written
+// or invalidated expire with their TTL.
+func logCacheError(operation string, err error) {
+
+	if err == nil {
+		return
+	}
+
+	providers.CacheErrors.Add(operation, 1)
+
+	log.Printf("cache %v error: %v", operation, err)
+}
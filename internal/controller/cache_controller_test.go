@@ -1,9 +1,16 @@
 package controller
 
 import (
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ernestngugi/todo/internal/mocks"
+	"github.com/ernestngugi/todo/internal/providers"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -58,5 +65,321 @@ func TestCacheController(t *testing.T) {
 			So(err, ShouldBeNil)
 			So(exists, ShouldBeFalse)
 		})
+
+		Convey("expires values after the TTL of their key family", func() {
+
+			now := time.Now()
+
+			redisProvider := mocks.NewMockRedisProvider()
+			redisProvider.SetClock(func() time.Time { return now })
+
+			cacheController := NewTestCacheController(redisProvider)
+			cacheController.jitter = 0
+			cacheController.ttls[CacheFamilyTodo] = time.Minute
+
+			err := cacheController.CacheValue("todo:todo-key:1", "todo")
+			So(err, ShouldBeNil)
+
+			err = cacheController.CacheValue("other:1", "other")
+			So(err, ShouldBeNil)
+
+			So(redisProvider.TTL("todo:todo-key:1"), ShouldEqual, time.Minute)
+			So(redisProvider.TTL("other:1"), ShouldEqual, time.Hour)
+
+			now = now.Add(time.Minute - time.Second)
+
+			exists, err := cacheController.Exists("todo:todo-key:1")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+
+			now = now.Add(time.Second)
+
+			exists, err = cacheController.Exists("todo:todo-key:1")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+
+			exists, err = cacheController.Exists("other:1")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+		})
+
+		Convey("spreads expiry within the jitter", func() {
+
+			redisProvider := mocks.NewMockRedisProvider()
+
+			now := time.Now()
+			redisProvider.SetClock(func() time.Time { return now })
+
+			cacheController := NewTestCacheController(redisProvider)
+
+			ttls := make(map[time.Duration]bool)
+
+			for i := 0; i < 20; i++ {
+
+				key := fmt.Sprintf("todo:todo-key:%d", i)
+
+				err := cacheController.CacheValue(key, i)
+				So(err, ShouldBeNil)
+
+				ttl := redisProvider.TTL(key)
+
+				So(ttl, ShouldBeBetweenOrEqual, 54*time.Minute, 66*time.Minute)
+
+				ttls[ttl] = true
+			}
+
+			So(len(ttls), ShouldBeGreaterThan, 1)
+		})
+
+		Convey("can extend the TTL of a cached value", func() {
+
+			now := time.Now()
+
+			redisProvider := mocks.NewMockRedisProvider()
+			redisProvider.SetClock(func() time.Time { return now })
+
+			_, err := redisProvider.SetWithTTL("key", "value", time.Minute)
+			So(err, ShouldBeNil)
+
+			ok, err := redisProvider.Expire("key", time.Hour)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			now = now.Add(30 * time.Minute)
+
+			_, err = redisProvider.Get("key")
+			So(err, ShouldBeNil)
+
+			ok, err = redisProvider.Expire("missing", time.Hour)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("loads and caches a value that is not cached", func() {
+
+			cacheController := NewTestCacheController(mocks.NewMockRedisProvider())
+
+			loads := 0
+
+			load := func() (any, error) {
+				loads++
+				return "value", nil
+			}
+
+			var value string
+
+			err := cacheController.GetOrLoad("todo:todo-key:1", &value, load)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "value")
+
+			err = cacheController.GetOrLoad("todo:todo-key:1", &value, load)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "value")
+			So(loads, ShouldEqual, 1)
+		})
+
+		Convey("shares one load between concurrent misses", func() {
+
+			cacheController := NewTestCacheController(mocks.NewMockRedisProvider())
+
+			var loads atomic.Int32
+			release := make(chan struct{})
+
+			var wg sync.WaitGroup
+
+			for i := 0; i < 10; i++ {
+
+				wg.Add(1)
+
+				go func() {
+					defer wg.Done()
+
+					var value int
+					err := cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+						loads.Add(1)
+						<-release
+						return 42, nil
+					})
+					if err != nil || value != 42 {
+						loads.Add(100)
+					}
+				}()
+			}
+
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			So(loads.Load(), ShouldEqual, 1)
+		})
+
+		Convey("waits for the value while another instance holds the lock", func() {
+
+			redisProvider := mocks.NewMockRedisProvider()
+			cacheController := NewTestCacheController(redisProvider)
+
+			locked, err := redisProvider.SetNX("todo:todo-key:1:lock", 1, time.Second)
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				cacheController.CacheValue("todo:todo-key:1", "cached")
+			}()
+
+			var value string
+
+			err = cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+				return "loaded", nil
+			})
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "cached")
+		})
+
+		Convey("does not cache a failed load", func() {
+
+			cacheController := NewTestCacheController(mocks.NewMockRedisProvider())
+
+			var value string
+
+			err := cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+				return nil, errors.New("load failed")
+			})
+			So(err, ShouldNotBeNil)
+
+			exists, err := cacheController.Exists("todo:todo-key:1")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+
+			exists, err = cacheController.Exists("todo:todo-key:1:lock")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+		})
+
+		Convey("counts from zero", func() {
+
+			cacheController := NewTestCacheController(mocks.NewMockRedisProvider())
+
+			counter, err := cacheController.Counter("todos:generation:owner-1")
+			So(err, ShouldBeNil)
+			So(counter, ShouldEqual, 0)
+
+			counter, err = cacheController.Increment("todos:generation:owner-1")
+			So(err, ShouldBeNil)
+			So(counter, ShouldEqual, 1)
+
+			counter, err = cacheController.Increment("todos:generation:owner-1")
+			So(err, ShouldBeNil)
+			So(counter, ShouldEqual, 2)
+
+			counter, err = cacheController.Counter("todos:generation:owner-1")
+			So(err, ShouldBeNil)
+			So(counter, ShouldEqual, 2)
+		})
+
+		Convey("loads without the cache while Redis is down", func() {
+
+			redisProvider := mocks.NewMockRedisProvider()
+			redisProvider.SetError(errors.New("connection refused"))
+
+			cacheController := NewTestCacheController(redisProvider)
+
+			failures := func() int64 {
+				counter, _ := providers.CacheErrors.Get("get").(*expvar.Int)
+				if counter == nil {
+					return 0
+				}
+				return counter.Value()
+			}
+
+			before := failures()
+
+			var value string
+
+			err := cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+				return "loaded", nil
+			})
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "loaded")
+			So(failures(), ShouldEqual, before+1)
+
+			err = cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+				return nil, errors.New("load failed")
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("keeps a lock that expired and was taken by another instance during the load", func() {
+
+			redisProvider := mocks.NewMockRedisProvider()
+			cacheController := NewTestCacheController(redisProvider)
+
+			now := time.Now()
+			redisProvider.SetClock(func() time.Time { return now })
+
+			var value string
+
+			err := cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+
+				now = now.Add(cacheLockTTL)
+
+				locked, err := redisProvider.SetNX("todo:todo-key:1:lock", "other", cacheLockTTL)
+				So(err, ShouldBeNil)
+				So(locked, ShouldBeTrue)
+
+				return "loaded", nil
+			})
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "loaded")
+
+			lock, err := redisProvider.Get("todo:todo-key:1:lock")
+			So(err, ShouldBeNil)
+			So(lock, ShouldEqual, "other")
+
+			exists, err := cacheController.Exists("todo:todo-key:1")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+		})
+
+		Convey("does not cache a value loaded before a write to its key", func() {
+
+			cacheController := NewTestCacheController(mocks.NewMockRedisProvider())
+
+			var value string
+
+			err := cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+
+				err := cacheController.RemoveFromCache("todo:todo-key:1")
+				So(err, ShouldBeNil)
+
+				return "stale", nil
+			})
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "stale")
+
+			exists, err := cacheController.Exists("todo:todo-key:1")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+
+			err = cacheController.GetOrLoad("todo:todo-key:1", &value, func() (any, error) {
+
+				err := cacheController.CacheValue("todo:todo-key:1", "fresh")
+				So(err, ShouldBeNil)
+
+				return "stale", nil
+			})
+			So(err, ShouldBeNil)
+
+			err = cacheController.GetCachedValue("todo:todo-key:1", &value)
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "fresh")
+		})
+
+		Convey("keeps locks and counters in the shared cache", func() {
+
+			So(SharedCacheKey("todo:todo-key:1:lock"), ShouldBeTrue)
+			So(SharedCacheKey(fmt.Sprintf(todoListGenerationPrefix, "owner-1")), ShouldBeTrue)
+			So(SharedCacheKey("todo:todo-key:1"), ShouldBeFalse)
+		})
 	})
 }
@@ -0,0 +1,220 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/repository"
+)
+
+const (
+	maxCommentLength   = 10000
+	maxCommentsPerPage = 100
+)
+
+type (
+	CommentController interface {
+		Comments(ctx context.Context, dB db.DB, todoID int64, page, per int) (*entities.CommentList, error)
+		CreateComment(ctx context.Context, dB db.DB, todoID int64, form *forms.CreateCommentForm) (*entities.Comment, error)
+		DeleteComment(ctx context.Context, dB db.DB, commentID int64) error
+		UpdateComment(ctx context.Context, dB db.DB, commentID int64, form *forms.UpdateCommentForm) (*entities.Comment, error)
+	}
+
+	commentController struct {
+		accessController  AccessController
+		commentRepository repository.CommentRepository
+		todoRepository    repository.TodoRepository
+	}
+)
+
+func NewCommentController(
+	accessController AccessController,
+	commentRepository repository.CommentRepository,
+	todoRepository repository.TodoRepository,
+) CommentController {
+	return &commentController{
+		accessController:  accessController,
+		commentRepository: commentRepository,
+		todoRepository:    todoRepository,
+	}
+}
+
+func NewTestCommentController() *commentController {
+	return &commentController{
+		accessController:  NewTestAccessController(),
+		commentRepository: repository.NewCommentRepository(),
+		todoRepository:    repository.NewTodoRepository(),
+	}
+}
+
+func (s *commentController) Comments(ctx context.Context, dB db.DB, todoID int64, page, per int) (*entities.CommentList, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.CommentList{}, err
+	}
+
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessRead)
+	if err != nil {
+		return &entities.CommentList{}, err
+	}
+
+	if page < 1 {
+		return &entities.CommentList{}, fmt.Errorf("page must be greater than zero")
+	}
+
+	if per < 1 || per > maxCommentsPerPage {
+		return &entities.CommentList{}, fmt.Errorf("per must be between 1 and %v", maxCommentsPerPage)
+	}
+
+	comments, err := s.commentRepository.Comments(ctx, dB, todo.ID, page, per)
+	if err != nil {
+		return &entities.CommentList{}, err
+	}
+
+	count, err := s.commentRepository.NumberOfComments(ctx, dB, todo.ID)
+	if err != nil {
+		return &entities.CommentList{}, err
+	}
+
+	commentList := &entities.CommentList{
+		Comments:   comments,
+		Pagination: entities.NewPagination(count, page, per),
+	}
+
+	return commentList, nil
+}
+
+func (s *commentController) CreateComment(ctx context.Context, dB db.DB, todoID int64, form *forms.CreateCommentForm) (*entities.Comment, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	err = validateCommentBody(form.Body)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	comment := &entities.Comment{
+		TodoID:   todo.ID,
+		AuthorID: user.ID,
+		Body:     form.Body,
+	}
+
+	err = s.commentRepository.Save(ctx, dB, comment)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+func (s *commentController) UpdateComment(ctx context.Context, dB db.DB, commentID int64, form *forms.UpdateCommentForm) (*entities.Comment, error) {
+
+	comment, err := s.commentForAuthor(ctx, dB, commentID)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	err = validateCommentBody(form.Body)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	if comment.Body == form.Body {
+		return comment, nil
+	}
+
+	timeNow := time.Now()
+
+	comment.Body = form.Body
+	comment.EditedAt = &timeNow
+
+	err = s.commentRepository.Save(ctx, dB, comment)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	return comment, nil
+}
+
+// DeleteComment hides the comment from the thread but keeps the row.
+func (s *commentController) DeleteComment(ctx context.Context, dB db.DB, commentID int64) error {
+
+	comment, err := s.commentForAuthor(ctx, dB, commentID)
+	if err != nil {
+		return err
+	}
+
+	timeNow := time.Now()
+	comment.DeletedAt = &timeNow
+
+	return s.commentRepository.Save(ctx, dB, comment)
+}
+
+// commentForAuthor loads a comment its author may still change, which also
+// requires that they can still see the todo it belongs to.
+func (s *commentController) commentForAuthor(ctx context.Context, dB db.DB, commentID int64) (*entities.Comment, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	comment, err := s.commentRepository.CommentByID(ctx, dB, commentID)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	_, err = s.todoForUser(ctx, dB, user, comment.TodoID, AccessRead)
+	if err != nil {
+		return &entities.Comment{}, err
+	}
+
+	if comment.AuthorID != user.ID {
+		return &entities.Comment{}, forbidden("only the author can change this comment")
+	}
+
+	return comment, nil
+}
+
+func (s *commentController) todoForUser(ctx context.Context, dB db.DB, user *entities.User, todoID int64, access Access) (*entities.Todo, error) {
+
+	todo, err := s.todoRepository.TodoByID(ctx, dB, todoID)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	err = s.accessController.AuthorizeTodo(ctx, dB, user, todo, access)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+func validateCommentBody(body string) error {
+
+	if strings.TrimSpace(body) == "" {
+		return fmt.Errorf("comment cannot be empty")
+	}
+
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return fmt.Errorf("comment cannot be longer than %v characters", maxCommentLength)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/mocks"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCommentController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestCommentController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		redisManager := mocks.NewMockRedisProvider()
+
+		commentController := NewTestCommentController()
+		todoController := NewTestTodoController(redisManager)
+
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
+		todo, err := repository.CreateTodo(ctx, dB, user)
+		So(err, ShouldBeNil)
+
+		Convey("can comment on a todo and edit the comment", func() {
+
+			comment, err := commentController.CreateComment(ctx, dB, todo.ID, &forms.CreateCommentForm{Body: "**first**"})
+			So(err, ShouldBeNil)
+
+			So(comment.AuthorID, ShouldEqual, user.ID)
+			So(comment.EditedAt, ShouldBeNil)
+
+			comment, err = commentController.UpdateComment(ctx, dB, comment.ID, &forms.UpdateCommentForm{Body: "**second**"})
+			So(err, ShouldBeNil)
+
+			So(comment.Body, ShouldEqual, "**second**")
+			So(comment.EditedAt, ShouldNotBeNil)
+
+			comments, err := commentController.Comments(ctx, dB, todo.ID, 1, 20)
+			So(err, ShouldBeNil)
+
+			So(len(comments.Comments), ShouldEqual, 1)
+			So(comments.Pagination.Count, ShouldEqual, 1)
+		})
+
+		Convey("rejects an empty comment", func() {
+
+			_, err := commentController.CreateComment(ctx, dB, todo.ID, &forms.CreateCommentForm{Body: "  "})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "comment cannot be empty")
+		})
+
+		Convey("only the author can change a comment", func() {
+
+			workspace, err := repository.CreateWorkspace(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			workspaceTodo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				WorkspaceID: &workspace.ID,
+			})
+			So(err, ShouldBeNil)
+
+			comment, err := commentController.CreateComment(ctx, dB, workspaceTodo.ID, &forms.CreateCommentForm{Body: "mine"})
+			So(err, ShouldBeNil)
+
+			editor, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			err = repository.NewWorkspaceRepository().SaveMember(ctx, dB, &entities.WorkspaceMember{
+				WorkspaceID: workspace.ID,
+				UserID:      editor.ID,
+				Role:        entities.WorkspaceRoleEditor,
+			})
+			So(err, ShouldBeNil)
+
+			err = commentController.DeleteComment(contexthelper.WithUser(ctx, editor), dB, comment.ID)
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("hides the comments of a deleted todo until it is restored", func() {
+
+			comment, err := commentController.CreateComment(ctx, dB, todo.ID, &forms.CreateCommentForm{Body: "bye"})
+			So(err, ShouldBeNil)
+
+			err = todoController.DeleteTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			_, err = commentController.Comments(ctx, dB, todo.ID, 1, 20)
+			So(err, ShouldNotBeNil)
+
+			_, err = todoController.RestoreTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			comments, err := commentController.Comments(ctx, dB, todo.ID, 1, 20)
+			So(err, ShouldBeNil)
+
+			So(len(comments.Comments), ShouldEqual, 1)
+			So(comments.Comments[0].ID, ShouldEqual, comment.ID)
+		})
+	}))
+}
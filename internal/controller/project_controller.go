@@ -0,0 +1,243 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+)
+
+type (
+	ProjectController interface {
+		CreateProject(ctx context.Context, dB db.DB, form *forms.CreateProjectForm) (*entities.Project, error)
+		DeleteProject(ctx context.Context, dB db.DB, projectID int64) error
+		ProjectByID(ctx context.Context, dB db.DB, projectID int64) (*entities.Project, error)
+		Projects(ctx context.Context, dB db.DB, includeArchived bool) ([]*entities.Project, error)
+		ProjectTodos(ctx context.Context, dB db.DB, projectID int64, filter *forms.Filter) (*entities.TodoList, error)
+		UpdateProject(ctx context.Context, dB db.DB, projectID int64, form *forms.UpdateProjectForm) (*entities.Project, error)
+	}
+
+	projectController struct {
+		cacheController   CacheController
+		projectRepository repository.ProjectRepository
+		todoController    TodoController
+	}
+)
+
+func NewProjectController(
+	cacheController CacheController,
+	projectRepository repository.ProjectRepository,
+	todoController TodoController,
+) ProjectController {
+	return &projectController{
+		cacheController:   cacheController,
+		projectRepository: projectRepository,
+		todoController:    todoController,
+	}
+}
+
+func NewTestProjectController(
+	redisProvider providers.Redis,
+) *projectController {
+	return &projectController{
+		cacheController:   NewTestCacheController(redisProvider),
+		projectRepository: repository.NewProjectRepository(),
+		todoController:    NewTestTodoController(redisProvider),
+	}
+}
+
+func (s *projectController) CreateProject(ctx context.Context, dB db.DB, form *forms.CreateProjectForm) (*entities.Project, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	name := strings.TrimSpace(form.Name)
+
+	err = utils.ValidateSingleName(name)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	project := &entities.Project{
+		OwnerID: user.ID,
+		Name:    name,
+	}
+
+	err = s.projectRepository.Save(ctx, dB, project)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	return project, nil
+}
+
+func (s *projectController) Projects(ctx context.Context, dB db.DB, includeArchived bool) ([]*entities.Project, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return []*entities.Project{}, err
+	}
+
+	return s.projectRepository.Projects(ctx, dB, user.ID, includeArchived)
+}
+
+func (s *projectController) ProjectByID(ctx context.Context, dB db.DB, projectID int64) (*entities.Project, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	return s.projectForUser(ctx, dB, user, projectID)
+}
+
+// ProjectTodos lists the todos of a project, archived or not, with the same
+// filters, sorting and pagination as the main todo list.
+func (s *projectController) ProjectTodos(ctx context.Context, dB db.DB, projectID int64, filter *forms.Filter) (*entities.TodoList, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.TodoList{}, err
+	}
+
+	project, err := s.projectForUser(ctx, dB, user, projectID)
+	if err != nil {
+		return &entities.TodoList{}, err
+	}
+
+	filter.ProjectID = project.ID
+
+	return s.todoController.Todos(ctx, dB, filter)
+}
+
+func (s *projectController) UpdateProject(ctx context.Context, dB db.DB, projectID int64, form *forms.UpdateProjectForm) (*entities.Project, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	project, err := s.projectForUser(ctx, dB, user, projectID)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	if form.Name != nil {
+
+		name := strings.TrimSpace(*form.Name)
+
+		err = utils.ValidateSingleName(name)
+		if err != nil {
+			return &entities.Project{}, err
+		}
+
+		project.Name = name
+	}
+
+	archiving := form.Archived != nil
+
+	if archiving {
+
+		if *form.Archived == project.Archived() {
+			return &entities.Project{}, fmt.Errorf("project is already %v", archivedState(project.Archived()))
+		}
+
+		project.ArchivedAt = nil
+
+		if *form.Archived {
+			timeNow := time.Now()
+			project.ArchivedAt = &timeNow
+		}
+	}
+
+	err = s.projectRepository.Save(ctx, dB, project)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	if archiving {
+
+		// listings leave out the todos of archived projects unless they ask
+		// for the project itself
+		err = invalidateTodoLists(s.cacheController, []string{fmt.Sprintf(todoListOwnerScope, project.OwnerID)})
+		logCacheError("invalidate", err)
+	}
+
+	return project, nil
+}
+
+func (s *projectController) DeleteProject(ctx context.Context, dB db.DB, projectID int64) error {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	project, err := s.projectForUser(ctx, dB, user, projectID)
+	if err != nil {
+		return err
+	}
+
+	// todos outlive their project, moving to a new version without it so
+	// that writes based on the old version are refused
+	return dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		todoIDs, err := s.projectRepository.DetachTodos(ctx, operations, project.ID)
+		if err != nil {
+			return err
+		}
+
+		err = s.projectRepository.DeleteProject(ctx, operations, project.ID)
+		if err != nil {
+			return err
+		}
+
+		db.AfterCommit(operations, func() {
+
+			for _, todoID := range todoIDs {
+				err := s.cacheController.RemoveFromCache(fmt.Sprintf(todoKeyPrefix, todoID))
+				logCacheError("del", err)
+			}
+
+			err := invalidateTodoLists(s.cacheController, []string{
+				fmt.Sprintf(todoListOwnerScope, project.OwnerID),
+				fmt.Sprintf(todoListProjectScope, project.ID),
+			})
+			logCacheError("invalidate", err)
+		})
+
+		return nil
+	})
+}
+
+func (s *projectController) projectForUser(ctx context.Context, dB db.DB, user *entities.User, projectID int64) (*entities.Project, error) {
+
+	project, err := s.projectRepository.ProjectByID(ctx, dB, projectID)
+	if err != nil {
+		return &entities.Project{}, err
+	}
+
+	if project.OwnerID != user.ID {
+		return &entities.Project{}, apperror.NewDatabaseError(sql.ErrNoRows)
+	}
+
+	return project, nil
+}
+
+func archivedState(archived bool) string {
+	if archived {
+		return "archived"
+	}
+	return "active"
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/mocks"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProjectController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestProjectController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		redisManager := mocks.NewMockRedisProvider()
+
+		projectController := NewTestProjectController(redisManager)
+		todoController := NewTestTodoController(redisManager)
+
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
+		project, err := projectController.CreateProject(ctx, dB, &forms.CreateProjectForm{Name: "home"})
+		So(err, ShouldBeNil)
+
+		Convey("can list the todos of a project", func() {
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				ProjectID:   &project.ID,
+			})
+			So(err, ShouldBeNil)
+
+			_, err = todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{Title: "test", Description: "test"})
+			So(err, ShouldBeNil)
+
+			todos, err := projectController.ProjectTodos(ctx, dB, project.ID, &forms.Filter{Page: 1, Per: 20})
+			So(err, ShouldBeNil)
+
+			So(len(todos.Todos), ShouldEqual, 1)
+			So(todos.Todos[0].ID, ShouldEqual, todo.ID)
+			So(todos.Pagination.Count, ShouldEqual, 1)
+		})
+
+		Convey("archiving a project hides its todos from the todo list", func() {
+
+			_, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				ProjectID:   &project.ID,
+			})
+			So(err, ShouldBeNil)
+
+			archived := true
+
+			project, err = projectController.UpdateProject(ctx, dB, project.ID, &forms.UpdateProjectForm{Archived: &archived})
+			So(err, ShouldBeNil)
+
+			So(project.ArchivedAt, ShouldNotBeNil)
+
+			todos, err := todoController.Todos(ctx, dB, &forms.Filter{})
+			So(err, ShouldBeNil)
+
+			So(len(todos.Todos), ShouldEqual, 0)
+
+			todos, err = projectController.ProjectTodos(ctx, dB, project.ID, &forms.Filter{})
+			So(err, ShouldBeNil)
+
+			So(len(todos.Todos), ShouldEqual, 1)
+		})
+
+		Convey("cannot add a todo to an archived project", func() {
+
+			archived := true
+
+			_, err := projectController.UpdateProject(ctx, dB, project.ID, &forms.UpdateProjectForm{Archived: &archived})
+			So(err, ShouldBeNil)
+
+			_, err = todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				ProjectID:   &project.ID,
+			})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "cannot add todos to an archived project")
+		})
+
+		Convey("cannot get a project belonging to another user", func() {
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			otherProject, err := repository.CreateProject(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			_, err = projectController.ProjectByID(ctx, dB, otherProject.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "sql: no rows in result set")
+		})
+
+		Convey("deleting a project keeps its todos", func() {
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				ProjectID:   &project.ID,
+			})
+			So(err, ShouldBeNil)
+
+			err = projectController.DeleteProject(ctx, dB, project.ID)
+			So(err, ShouldBeNil)
+
+			foundTodo, err := todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.ProjectID, ShouldBeNil)
+		})
+
+		Convey("deleting a project moves its todos to a new version", func() {
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				ProjectID:   &project.ID,
+			})
+			So(err, ShouldBeNil)
+
+			err = projectController.DeleteProject(ctx, dB, project.ID)
+			So(err, ShouldBeNil)
+
+			title := "stale"
+
+			_, err = todoController.UpdateTodo(contexthelper.WithIfMatch(ctx, todo.ETag()), dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldNotBeNil)
+		})
+	}))
+}
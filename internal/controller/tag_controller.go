@@ -0,0 +1,226 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+)
+
+type (
+	TagController interface {
+		CreateTag(ctx context.Context, dB db.DB, form *forms.CreateTagForm) (*entities.Tag, error)
+		DeleteTag(ctx context.Context, dB db.DB, tagID int64) error
+		TagByID(ctx context.Context, dB db.DB, tagID int64) (*entities.Tag, error)
+		Tags(ctx context.Context, dB db.DB) ([]*entities.Tag, error)
+		UpdateTag(ctx context.Context, dB db.DB, tagID int64, form *forms.UpdateTagForm) (*entities.Tag, error)
+	}
+
+	tagController struct {
+		cacheController CacheController
+		tagRepository   repository.TagRepository
+		todoRepository  repository.TodoRepository
+	}
+)
+
+func NewTagController(
+	cacheController CacheController,
+	tagRepository repository.TagRepository,
+	todoRepository repository.TodoRepository,
+) TagController {
+	return &tagController{
+		cacheController: cacheController,
+		tagRepository:   tagRepository,
+		todoRepository:  todoRepository,
+	}
+}
+
+func NewTestTagController(
+	redisProvider providers.Redis,
+) *tagController {
+	return &tagController{
+		cacheController: NewTestCacheController(redisProvider),
+		tagRepository:   repository.NewTagRepository(),
+		todoRepository:  repository.NewTodoRepository(),
+	}
+}
+
+func (s *tagController) CreateTag(ctx context.Context, dB db.DB, form *forms.CreateTagForm) (*entities.Tag, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	err = s.validateName(ctx, dB, user, form.Name)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	tag := &entities.Tag{
+		OwnerID: user.ID,
+		Name:    form.Name,
+	}
+
+	err = s.tagRepository.Save(ctx, dB, tag)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	return tag, nil
+}
+
+func (s *tagController) Tags(ctx context.Context, dB db.DB) ([]*entities.Tag, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return []*entities.Tag{}, err
+	}
+
+	return s.tagRepository.Tags(ctx, dB, user.ID)
+}
+
+func (s *tagController) TagByID(ctx context.Context, dB db.DB, tagID int64) (*entities.Tag, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	return s.tagForUser(ctx, dB, user, tagID)
+}
+
+func (s *tagController) UpdateTag(ctx context.Context, dB db.DB, tagID int64, form *forms.UpdateTagForm) (*entities.Tag, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	tag, err := s.tagForUser(ctx, dB, user, tagID)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	if strings.EqualFold(strings.TrimSpace(form.Name), tag.Name) {
+		return tag, nil
+	}
+
+	err = s.validateName(ctx, dB, user, form.Name)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	tag.Name = form.Name
+
+	err = s.tagRepository.Save(ctx, dB, tag)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	todos, err := s.taggedTodos(ctx, dB, tag.ID)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	s.removeTodosFromCache(todos)
+
+	return tag, nil
+}
+
+func (s *tagController) DeleteTag(ctx context.Context, dB db.DB, tagID int64) error {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	tag, err := s.tagForUser(ctx, dB, user, tagID)
+	if err != nil {
+		return err
+	}
+
+	todos, err := s.taggedTodos(ctx, dB, tag.ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.tagRepository.DeleteTag(ctx, dB, tag.ID)
+	if err != nil {
+		return err
+	}
+
+	s.removeTodosFromCache(todos)
+
+	return nil
+}
+
+func (s *tagController) tagForUser(ctx context.Context, dB db.DB, user *entities.User, tagID int64) (*entities.Tag, error) {
+
+	tag, err := s.tagRepository.TagByID(ctx, dB, tagID)
+	if err != nil {
+		return &entities.Tag{}, err
+	}
+
+	if tag.OwnerID != user.ID {
+		return &entities.Tag{}, apperror.NewDatabaseError(sql.ErrNoRows)
+	}
+
+	return tag, nil
+}
+
+func (s *tagController) validateName(ctx context.Context, dB db.DB, user *entities.User, name string) error {
+
+	err := utils.ValidateSingleName(strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(name, ",") {
+		return fmt.Errorf("tag name cannot contain a comma")
+	}
+
+	_, err = s.tagRepository.TagByName(ctx, dB, user.ID, name)
+	if err == nil {
+		return apperror.Wrap(errors.New("tag already exists")).SetHttpStatusCode(http.StatusConflict)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *tagController) taggedTodos(ctx context.Context, dB db.DB, tagID int64) ([]*entities.Todo, error) {
+
+	todoIDs, err := s.tagRepository.TodoIDsByTag(ctx, dB, tagID)
+	if err != nil {
+		return []*entities.Todo{}, err
+	}
+
+	return s.todoRepository.TodosByIDs(ctx, dB, todoIDs)
+}
+
+// removeTodosFromCache drops cached todos and listings that embed a tag so
+// they are not served with a stale or deleted tag.
+func (s *tagController) removeTodosFromCache(todos []*entities.Todo) {
+
+	for _, todo := range todos {
+		err := s.cacheController.RemoveFromCache(fmt.Sprintf(todoKeyPrefix, todo.ID))
+		logCacheError("del", err)
+	}
+
+	err := invalidateTodoLists(s.cacheController, todoListScopes(todos...))
+	logCacheError("invalidate", err)
+}
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/mocks"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTagController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestTagController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		redisManager := mocks.NewMockRedisProvider()
+
+		tagController := NewTestTagController(redisManager)
+		todoController := NewTestTodoController(redisManager)
+
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
+		Convey("can create a tag", func() {
+
+			tag, err := tagController.CreateTag(ctx, dB, &forms.CreateTagForm{Name: "Work"})
+			So(err, ShouldBeNil)
+
+			So(tag.ID, ShouldNotBeZeroValue)
+			So(tag.OwnerID, ShouldEqual, user.ID)
+			So(tag.Name, ShouldEqual, "work")
+		})
+
+		Convey("cannot create a duplicate tag", func() {
+
+			_, err := tagController.CreateTag(ctx, dB, &forms.CreateTagForm{Name: "work"})
+			So(err, ShouldBeNil)
+
+			_, err = tagController.CreateTag(ctx, dB, &forms.CreateTagForm{Name: "WORK"})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "tag already exists")
+		})
+
+		Convey("cannot get a tag belonging to another user", func() {
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			tag, err := repository.CreateTag(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			_, err = tagController.TagByID(ctx, dB, tag.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "sql: no rows in result set")
+		})
+
+		Convey("can attach tags when creating and updating a todo", func() {
+
+			work, err := tagController.CreateTag(ctx, dB, &forms.CreateTagForm{Name: "work"})
+			So(err, ShouldBeNil)
+
+			home, err := tagController.CreateTag(ctx, dB, &forms.CreateTagForm{Name: "home"})
+			So(err, ShouldBeNil)
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				TagIDs:      []int64{work.ID},
+			})
+			So(err, ShouldBeNil)
+
+			So(len(todo.Tags), ShouldEqual, 1)
+
+			todo, err = todoController.UpdateTodo(ctx, dB, todo.ID, &forms.UpdateTodoForm{
+				AttachTagIDs: []int64{home.ID},
+				DetachTagIDs: []int64{work.ID},
+			})
+			So(err, ShouldBeNil)
+
+			So(len(todo.Tags), ShouldEqual, 1)
+			So(todo.Tags[0].ID, ShouldEqual, home.ID)
+		})
+
+		Convey("cannot attach a tag belonging to another user", func() {
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			tag, err := repository.CreateTag(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				TagIDs:      []int64{tag.ID},
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("removes tagged todos from the cache when a tag is renamed", func() {
+
+			tag, err := tagController.CreateTag(ctx, dB, &forms.CreateTagForm{Name: "work"})
+			So(err, ShouldBeNil)
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:       "test",
+				Description: "test",
+				TagIDs:      []int64{tag.ID},
+			})
+			So(err, ShouldBeNil)
+
+			exists, err := redisManager.Exists(fmt.Sprintf(todoKeyPrefix, todo.ID))
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+
+			_, err = tagController.UpdateTag(ctx, dB, tag.ID, &forms.UpdateTagForm{Name: "office"})
+			So(err, ShouldBeNil)
+
+			exists, err = redisManager.Exists(fmt.Sprintf(todoKeyPrefix, todo.ID))
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+
+			foundTodo, err := todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.Tags[0].Name, ShouldEqual, "office")
+		})
+
+		Convey("can delete a tag", func() {
+
+			tag, err := tagController.CreateTag(ctx, dB, &forms.CreateTagForm{Name: "work"})
+			So(err, ShouldBeNil)
+
+			err = tagController.DeleteTag(ctx, dB, tag.ID)
+			So(err, ShouldBeNil)
+
+			tags, err := tagController.Tags(ctx, dB)
+			So(err, ShouldBeNil)
+
+			So(len(tags), ShouldEqual, 0)
+		})
+	}))
+}
@@ -2,35 +2,78 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ernestngugi/todo/internal/apperror"
 	"github.com/ernestngugi/todo/internal/db"
 	"github.com/ernestngugi/todo/internal/entities"
 	"github.com/ernestngugi/todo/internal/forms"
 	"github.com/ernestngugi/todo/internal/providers"
 	"github.com/ernestngugi/todo/internal/repository"
 	"github.com/ernestngugi/todo/internal/utils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
 )
 
+var errRecurrenceWithoutDueAt = errors.New("recurring todos need a due date")
+
+// todoTransitions lists the statuses a todo may move to from each status.
+// Finished todos have to be reopened before work on them can resume.
+var todoTransitions = map[entities.TodoStatus][]entities.TodoStatus{
+	entities.TodoStatusOpen:       {entities.TodoStatusInProgress, entities.TodoStatusBlocked, entities.TodoStatusDone, entities.TodoStatusCancelled},
+	entities.TodoStatusInProgress: {entities.TodoStatusOpen, entities.TodoStatusBlocked, entities.TodoStatusDone, entities.TodoStatusCancelled},
+	entities.TodoStatusBlocked:    {entities.TodoStatusOpen, entities.TodoStatusInProgress, entities.TodoStatusCancelled},
+	entities.TodoStatusDone:       {entities.TodoStatusOpen},
+	entities.TodoStatusCancelled:  {entities.TodoStatusOpen},
+}
+
 const (
-	todoKeyPrefix = "todo:todo-key:%v"
+	dueAtClockSkew           = time.Minute
+	todoKeyPrefix            = CacheFamilyTodo + ":todo-key:%v"
+	todoListGenerationPrefix = CacheFamilyTodoList + cacheCounterInfix + "%v"
+	todoListKeyPrefix        = CacheFamilyTodoList + ":list:%v:%v:%x"
+	todoListOwnerScope       = "owner-%v"
+	todoListProjectScope     = "project-%v"
+	todoListWorkspaceScope   = "workspace-%v"
 )
 
 type (
 	TodoController interface {
-		CompleteTodo(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error)
+		AssignTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.AssignTodoForm) (*entities.Todo, error)
+		CompleteTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.CompleteTodoForm) (*entities.Todo, error)
 		CreateTodo(ctx context.Context, dB db.DB, form *forms.CreateTodoForm) (*entities.Todo, error)
 		DeleteTodo(ctx context.Context, dB db.DB, todoID int64) error
+		RestoreTodo(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error)
+		TodoActivities(ctx context.Context, dB db.DB, todoID int64) ([]*entities.TodoActivity, error)
 		TodoByID(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error)
+		TodoHistory(ctx context.Context, dB db.DB, todoID int64) ([]*entities.TodoEvent, error)
+		TransitionTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.TransitionTodoForm) (*entities.Todo, error)
 		Todos(ctx context.Context, dB db.DB, filter *forms.Filter) (*entities.TodoList, error)
+		Trash(ctx context.Context, dB db.DB, filter *forms.Filter) (*entities.TodoList, error)
+		TodosAssignedToMe(ctx context.Context, dB db.DB, filter *forms.Filter) (*entities.TodoList, error)
+		TodosDueToday(ctx context.Context, dB db.DB, location *time.Location, filter *forms.Filter) (*entities.TodoList, error)
+		UnassignTodo(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error)
 		UpdateTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.UpdateTodoForm) (*entities.Todo, error)
 	}
 
 	todoController struct {
-		cacheController CacheController
-		todoRepository  repository.TodoRepository
+		accessController       AccessController
+		cacheController        CacheController
+		cursorCodec            *utils.CursorCodec
+		projectRepository      repository.ProjectRepository
+		tagRepository          repository.TagRepository
+		todoActivityRepository repository.TodoActivityRepository
+		todoEventRepository    repository.TodoEventRepository
+		todoItemRepository     repository.TodoItemRepository
+		todoRepository         repository.TodoRepository
+		workspaceRepository    repository.WorkspaceRepository
 	}
 )
 
@@ -39,68 +82,140 @@ func NewTestTodoController(
 ) *todoController {
 	cacheController := NewTestCacheController(redisProvider)
 	return &todoController{
-		cacheController: cacheController,
-		todoRepository:  repository.NewTodoRepository(),
+		accessController:       NewTestAccessController(),
+		cacheController:        cacheController,
+		cursorCodec:            utils.NewCursorCodec("test-cursor-secret"),
+		projectRepository:      repository.NewProjectRepository(),
+		tagRepository:          repository.NewTagRepository(),
+		todoActivityRepository: repository.NewTodoActivityRepository(),
+		todoEventRepository:    repository.NewTodoEventRepository(),
+		todoItemRepository:     repository.NewTodoItemRepository(),
+		todoRepository:         repository.NewTodoRepository(),
+		workspaceRepository:    repository.NewWorkspaceRepository(),
 	}
 }
 
 func NewTodoController(
+	accessController AccessController,
 	cacheController CacheController,
+	cursorCodec *utils.CursorCodec,
+	projectRepository repository.ProjectRepository,
+	tagRepository repository.TagRepository,
+	todoActivityRepository repository.TodoActivityRepository,
+	todoEventRepository repository.TodoEventRepository,
+	todoItemRepository repository.TodoItemRepository,
 	todoRepository repository.TodoRepository,
+	workspaceRepository repository.WorkspaceRepository,
 ) TodoController {
 	return &todoController{
-		cacheController: cacheController,
-		todoRepository:  todoRepository,
+		accessController:       accessController,
+		cacheController:        cacheController,
+		cursorCodec:            cursorCodec,
+		projectRepository:      projectRepository,
+		tagRepository:          tagRepository,
+		todoActivityRepository: todoActivityRepository,
+		todoEventRepository:    todoEventRepository,
+		todoItemRepository:     todoItemRepository,
+		todoRepository:         todoRepository,
+		workspaceRepository:    workspaceRepository,
 	}
 }
 
 func (s *todoController) TodoByID(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error) {
-	exist, err := s.cacheController.Exists(s.generateCacheKey(todoID))
+
+	user, err := authenticatedUser(ctx)
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	var todo *entities.Todo
-
-	if exist {
-
-		err = s.cacheController.GetCachedValue(s.generateCacheKey(todoID), &todo)
-		if err != nil {
-			return &entities.Todo{}, err
-		}
+	return s.todoForUser(ctx, dB, user, todoID, AccessRead)
+}
 
-		return todo, nil
-	}
+func (s *todoController) CreateTodo(ctx context.Context, dB db.DB, form *forms.CreateTodoForm) (*entities.Todo, error) {
 
-	todo, err = s.todoRepository.TodoByID(ctx, dB, todoID)
+	user, err := authenticatedUser(ctx)
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	return todo, nil
-}
-
-func (s *todoController) CreateTodo(ctx context.Context, dB db.DB, form *forms.CreateTodoForm) (*entities.Todo, error) {
-
-	err := utils.ValidateSingleName(form.Title)
+	err = utils.ValidateSingleName(form.Title)
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
 	todo := &entities.Todo{
-		Title: form.Title,
+		OwnerID: user.ID,
+		Title:   form.Title,
 	}
 
 	if strings.TrimSpace(form.Description) != "" {
 		todo.Description = form.Description
 	}
 
-	err = s.todoRepository.Save(ctx, dB, todo)
+	if form.DueAt != nil {
+		err = validateDueAt(*form.DueAt)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+		todo.DueAt = form.DueAt
+	}
+
+	if strings.TrimSpace(form.Priority) != "" {
+		todo.Priority, err = entities.ParsePriority(form.Priority)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+	}
+
+	if form.WorkspaceID != nil {
+		_, err = s.accessController.AuthorizeWorkspace(ctx, dB, user, *form.WorkspaceID, AccessWrite)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+		todo.WorkspaceID = form.WorkspaceID
+	}
+
+	if form.ProjectID != nil {
+		err = s.validateProject(ctx, dB, user, todo, *form.ProjectID)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+		todo.ProjectID = form.ProjectID
+	}
+
+	if strings.TrimSpace(form.RecurrenceRule) != "" {
+		err = setRecurrenceRule(todo, form.RecurrenceRule)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+	}
+
+	todo.Tags, err = s.tagsForUser(ctx, dB, user, form.TagIDs)
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	err = s.cacheTodo(todo)
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.todoRepository.Save(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		err = s.tagRepository.AttachTags(ctx, operations, todo.ID, form.TagIDs)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventCreated, todo, entities.DiffTodos(&entities.Todo{}, todo))
+		if err != nil {
+			return err
+		}
+
+		s.cacheAfterCommit(operations, todo)
+
+		return nil
+	})
 	if err != nil {
 		return &entities.Todo{}, err
 	}
@@ -110,53 +225,131 @@ func (s *todoController) CreateTodo(ctx context.Context, dB db.DB, form *forms.C
 
 func (s *todoController) UpdateTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.UpdateTodoForm) (*entities.Todo, error) {
 
-	exist, err := s.cacheController.Exists(s.generateCacheKey(todoID))
+	user, err := authenticatedUser(ctx)
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	var todo *entities.Todo
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	before := *todo
+
+	if form.Title != nil {
+		err := utils.ValidateSingleName(*form.Title)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+		todo.Title = *form.Title
+	}
+
+	if form.Description != nil {
+		if strings.TrimSpace(*form.Description) != "" {
+			todo.Description = *form.Description
+		}
+	}
+
+	if form.ClearDueAt && form.DueAt != nil {
+		return &entities.Todo{}, fmt.Errorf("cannot set and clear the due date at the same time")
+	}
 
-	if exist {
+	if form.ClearDueAt {
+		todo.DueAt = nil
+	}
 
-		err = s.cacheController.GetCachedValue(s.generateCacheKey(todoID), &todo)
+	if form.DueAt != nil {
+		err := validateDueAt(*form.DueAt)
 		if err != nil {
 			return &entities.Todo{}, err
 		}
-	} else {
+		todo.DueAt = form.DueAt
+	}
 
-		todo, err = s.todoRepository.TodoByID(ctx, dB, todoID)
+	if form.Priority != nil {
+		todo.Priority, err = entities.ParsePriority(*form.Priority)
 		if err != nil {
 			return &entities.Todo{}, err
 		}
+	}
 
+	if form.ClearProject && form.ProjectID != nil {
+		return &entities.Todo{}, fmt.Errorf("cannot set and clear the project at the same time")
 	}
 
-	if form.Title != nil {
-		err := utils.ValidateSingleName(*form.Title)
+	if form.ClearProject {
+		todo.ProjectID = nil
+	}
+
+	if form.ProjectID != nil {
+		err = s.validateProject(ctx, dB, user, todo, *form.ProjectID)
 		if err != nil {
 			return &entities.Todo{}, err
 		}
-		todo.Title = *form.Title
+		todo.ProjectID = form.ProjectID
 	}
 
-	if form.Description != nil {
-		if strings.TrimSpace(*form.Description) != "" {
-			todo.Description = *form.Description
+	if form.ClearRecurrenceRule && form.RecurrenceRule != nil {
+		return &entities.Todo{}, fmt.Errorf("cannot set and clear the recurrence rule at the same time")
+	}
+
+	if form.ClearRecurrenceRule {
+		todo.RecurrenceRule = nil
+	}
+
+	if form.RecurrenceRule != nil {
+		err = setRecurrenceRule(todo, *form.RecurrenceRule)
+		if err != nil {
+			return &entities.Todo{}, err
 		}
 	}
 
-	err = s.todoRepository.Save(ctx, dB, todo)
-	if err != nil {
-		return &entities.Todo{}, err
+	if todo.RecurrenceRule != nil && todo.DueAt == nil {
+		return &entities.Todo{}, errRecurrenceWithoutDueAt
 	}
 
-	err = s.removeFromCache(todo.ID)
+	_, err = s.tagsForUser(ctx, dB, user, form.AttachTagIDs)
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	err = s.cacheTodo(todo)
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.todoRepository.Save(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		if len(form.AttachTagIDs) > 0 || len(form.DetachTagIDs) > 0 {
+
+			err = s.tagRepository.DetachTags(ctx, operations, todo.ID, form.DetachTagIDs)
+			if err != nil {
+				return err
+			}
+
+			err = s.tagRepository.AttachTags(ctx, operations, todo.ID, form.AttachTagIDs)
+			if err != nil {
+				return err
+			}
+
+			tagsByTodo, err := s.tagRepository.TagsForTodos(ctx, operations, []int64{todo.ID})
+			if err != nil {
+				return err
+			}
+
+			todo.Tags = tagsByTodo[todo.ID]
+		}
+
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventUpdated, todo, entities.DiffTodos(&before, todo))
+		if err != nil {
+			return err
+		}
+
+		s.cacheAfterCommit(operations, todo, &before)
+
+		return nil
+	})
 	if err != nil {
 		return &entities.Todo{}, err
 	}
@@ -164,50 +357,146 @@ func (s *todoController) UpdateTodo(ctx context.Context, dB db.DB, todoID int64,
 	return todo, nil
 }
 
-func (s *todoController) CompleteTodo(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error) {
+func (s *todoController) CompleteTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.CompleteTodoForm) (*entities.Todo, error) {
+	return s.TransitionTodo(ctx, dB, todoID, &forms.TransitionTodoForm{
+		OpenItems: form.OpenItems,
+		Status:    entities.TodoStatusDone,
+	})
+}
+
+// TransitionTodo moves a todo to another status, following todoTransitions.
+func (s *todoController) TransitionTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.TransitionTodoForm) (*entities.Todo, error) {
 
-	exist, err := s.cacheController.Exists(s.generateCacheKey(todoID))
+	user, err := authenticatedUser(ctx)
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	var todo *entities.Todo
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
 
-	if exist {
+	status, err := entities.ParseTodoStatus(string(form.Status))
+	if err != nil {
+		return &entities.Todo{}, err
+	}
 
-		err = s.cacheController.GetCachedValue(s.generateCacheKey(todoID), &todo)
-		if err != nil {
-			return &entities.Todo{}, err
-		}
-	} else {
+	if todo.Status == status {
 
-		todo, err = s.todoRepository.TodoByID(ctx, dB, todoID)
-		if err != nil {
-			return &entities.Todo{}, err
+		if status == entities.TodoStatusDone {
+			return &entities.Todo{}, fmt.Errorf("todo has been marked as complete")
 		}
 
+		return &entities.Todo{}, fmt.Errorf("todo is already %v", status)
 	}
 
-	if todo.Completed {
-		return &entities.Todo{}, fmt.Errorf("todo has been marked as complete")
+	if !slices.Contains(todoTransitions[todo.Status], status) {
+		return &entities.Todo{}, fmt.Errorf("cannot move a todo from %v to %v", todo.Status, status)
 	}
 
-	timeNow := time.Now()
+	if status == entities.TodoStatusDone {
+		return s.completeTodo(ctx, dB, user, todo, form.OpenItems)
+	}
+
+	before := *todo
+
+	todo.SetStatus(status, time.Now())
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.todoRepository.Save(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventUpdated, todo, entities.DiffTodos(&before, todo))
+		if err != nil {
+			return err
+		}
 
-	todo.Completed = true
-	todo.CompletedAt = &timeNow
+		s.cacheAfterCommit(operations, todo)
 
-	err = s.todoRepository.Save(ctx, dB, todo)
+		return nil
+	})
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	err = s.removeFromCache(todo.ID)
+	return todo, nil
+}
+
+// completeTodo settles the checklist according to openItems and starts the
+// next occurrence of a recurring todo.
+func (s *todoController) completeTodo(ctx context.Context, dB db.DB, user *entities.User, todo *entities.Todo, openItems string) (*entities.Todo, error) {
+
+	if openItems == "" {
+		openItems = forms.OpenItemsRefuse
+	}
+
+	if openItems != forms.OpenItemsRefuse && openItems != forms.OpenItemsCascade {
+		return &entities.Todo{}, fmt.Errorf("invalid open_items argument %v, expected %v or %v", openItems, forms.OpenItemsRefuse, forms.OpenItemsCascade)
+	}
+
+	itemCounts, err := s.todoItemRepository.ItemCountsForTodos(ctx, dB, []int64{todo.ID})
 	if err != nil {
 		return &entities.Todo{}, err
 	}
 
-	err = s.cacheTodo(todo)
+	todo.SetItemCounts(itemCounts[todo.ID])
+
+	if todo.HasOpenItems() && openItems == forms.OpenItemsRefuse {
+		return &entities.Todo{}, fmt.Errorf("todo has %d open items", todo.ItemsTotal-todo.ItemsCompleted)
+	}
+
+	timeNow := time.Now()
+
+	before := *todo
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		if todo.HasOpenItems() {
+
+			err := s.todoItemRepository.CompleteOpenItems(ctx, operations, todo.ID, timeNow)
+			if err != nil {
+				return err
+			}
+		}
+
+		if todo.RecurrenceRule != nil && todo.SeriesID == nil {
+			todo.SeriesID = &todo.ID
+		}
+
+		todo.SetStatus(entities.TodoStatusDone, timeNow)
+
+		err := s.todoRepository.Save(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventCompleted, todo, entities.DiffTodos(&before, todo))
+		if err != nil {
+			return err
+		}
+
+		todo.SetItemCounts(entities.TodoItemCounts{Completed: todo.ItemsTotal, Total: todo.ItemsTotal})
+
+		s.cacheAfterCommit(operations, todo)
+
+		nextTodo, err := s.createNextOccurrence(ctx, operations, todo)
+		if err != nil || nextTodo == nil {
+			return err
+		}
+
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventCreated, nextTodo, entities.DiffTodos(&entities.Todo{}, nextTodo))
+		if err != nil {
+			return err
+		}
+
+		s.cacheAfterCommit(operations, nextTodo)
+
+		return nil
+	})
 	if err != nil {
 		return &entities.Todo{}, err
 	}
@@ -217,42 +506,143 @@ func (s *todoController) CompleteTodo(ctx context.Context, dB db.DB, todoID int6
 
 func (s *todoController) DeleteTodo(ctx context.Context, dB db.DB, todoID int64) error {
 
-	exist, err := s.cacheController.Exists(s.generateCacheKey(todoID))
+	user, err := authenticatedUser(ctx)
 	if err != nil {
 		return err
 	}
 
-	var todo *entities.Todo
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessWrite)
+	if err != nil {
+		return err
+	}
 
-	if exist {
+	before := *todo
 
-		err = s.cacheController.GetCachedValue(s.generateCacheKey(todoID), &todo)
+	// comments, attachments and checklist items stay with the todo in the
+	// trash and are only removed when it is purged
+	return dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.todoRepository.DeleteTodo(ctx, operations, todo)
 		if err != nil {
 			return err
 		}
-	} else {
 
-		todo, err = s.todoRepository.TodoByID(ctx, dB, todoID)
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventDeleted, todo, entities.DiffTodos(&before, todo))
 		if err != nil {
 			return err
 		}
 
+		s.uncacheAfterCommit(operations, todo)
+
+		return nil
+	})
+}
+
+func (s *todoController) RestoreTodo(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	todo, err := s.todoRepository.DeletedTodoByID(ctx, dB, todoID)
+	if err != nil {
+		return &entities.Todo{}, err
 	}
 
-	if todo.Completed {
-		return fmt.Errorf("cannot a todo that has been completed")
+	err = s.accessController.AuthorizeTodo(ctx, dB, user, todo, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, err
 	}
 
-	err = s.removeFromCache(todo.ID)
+	err = checkIfMatch(ctx, todo)
 	if err != nil {
-		return err
+		return &entities.Todo{}, err
+	}
+
+	before := *todo
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.todoRepository.RestoreTodo(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventRestored, todo, entities.DiffTodos(&before, todo))
+		if err != nil {
+			return err
+		}
+
+		s.cacheAfterCommit(operations, todo)
+
+		return nil
+	})
+	if err != nil {
+		return &entities.Todo{}, err
 	}
 
-	return s.todoRepository.DeleteTodo(ctx, dB, todo.ID)
+	return todo, nil
 }
 
 func (s *todoController) Todos(ctx context.Context, dB db.DB, filter *forms.Filter) (*entities.TodoList, error) {
 
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.TodoList{}, err
+	}
+
+	if filter.WorkspaceID > 0 {
+		_, err = s.accessController.AuthorizeWorkspace(ctx, dB, user, filter.WorkspaceID, AccessRead)
+		if err != nil {
+			return &entities.TodoList{}, err
+		}
+	}
+
+	if filter.AssignedToMe {
+		filter.AssigneeID = user.ID
+	}
+
+	filter.OwnerID = user.ID
+	filter.Sort = withDueAtAfterPriority(filter.Sort)
+
+	if filter.CursorToken != "" {
+
+		filter.Cursor, err = s.cursorCodec.Decode(filter.CursorToken)
+		if err != nil {
+			return &entities.TodoList{}, err
+		}
+
+		if filter.Cursor.Sort != forms.SortKey(filter.Sort) {
+			return &entities.TodoList{}, fmt.Errorf("cursor does not match the requested sort")
+		}
+	}
+
+	scope, cacheable := todoListScope(filter)
+	if !cacheable {
+		return s.todoList(ctx, dB, filter)
+	}
+
+	key, err := s.todoListCacheKey(scope, filter)
+	if err != nil {
+		logCacheError("get", err)
+		return s.todoList(ctx, dB, filter)
+	}
+
+	var todoList *entities.TodoList
+
+	err = s.cacheController.GetOrLoad(key, &todoList, func() (any, error) {
+		return s.todoList(ctx, dB, filter)
+	})
+	if err != nil {
+		return &entities.TodoList{}, err
+	}
+
+	return todoList, nil
+}
+
+func (s *todoController) todoList(ctx context.Context, dB db.DB, filter *forms.Filter) (*entities.TodoList, error) {
+
 	todos, err := s.todoRepository.Todos(ctx, dB, filter)
 	if err != nil {
 		return &entities.TodoList{}, err
@@ -263,6 +653,10 @@ func (s *todoController) Todos(ctx context.Context, dB db.DB, filter *forms.Filt
 		return &entities.TodoList{}, err
 	}
 
+	if filter.Limit > 0 {
+		return s.cursorTodoList(todos, count, filter)
+	}
+
 	todoList := &entities.TodoList{
 		Todos:      todos,
 		Pagination: entities.NewPagination(count, filter.Page, filter.Per),
@@ -271,14 +665,640 @@ func (s *todoController) Todos(ctx context.Context, dB db.DB, filter *forms.Filt
 	return todoList, nil
 }
 
-func (s *todoController) generateCacheKey(todoID int64) string {
-	return fmt.Sprintf(todoKeyPrefix, todoID)
+// Trash lists the deleted todos that have not been purged yet, with the same
+// filters and access rules as Todos.
+func (s *todoController) Trash(ctx context.Context, dB db.DB, filter *forms.Filter) (*entities.TodoList, error) {
+
+	filter.Deleted = true
+
+	return s.Todos(ctx, dB, filter)
 }
 
-func (s *todoController) cacheTodo(todo *entities.Todo) error {
-	return s.cacheController.CacheValue(s.generateCacheKey(todo.ID), todo)
+// TodosAssignedToMe lists the todos assigned to the caller across their
+// personal todos and every workspace they belong to.
+func (s *todoController) TodosAssignedToMe(ctx context.Context, dB db.DB, filter *forms.Filter) (*entities.TodoList, error) {
+
+	filter.AssignedToMe = true
+	filter.IncludeWorkspaces = true
+	filter.WorkspaceID = 0
+
+	return s.Todos(ctx, dB, filter)
 }
 
-func (s *todoController) removeFromCache(todoID int64) error {
-	return s.cacheController.RemoveFromCache(s.generateCacheKey(todoID))
+func (s *todoController) TodosDueToday(ctx context.Context, dB db.DB, location *time.Location, filter *forms.Filter) (*entities.TodoList, error) {
+
+	now := time.Now().In(location)
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	endOfDay := startOfDay.AddDate(0, 0, 1)
+
+	filter.DueAfter = &startOfDay
+	filter.DueBefore = &endOfDay
+
+	return s.Todos(ctx, dB, filter)
+}
+
+func (s *todoController) AssignTodo(ctx context.Context, dB db.DB, todoID int64, form *forms.AssignTodoForm) (*entities.Todo, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	err = s.validateAssignee(ctx, dB, todo, form.AssigneeID)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	if todo.AssigneeID != nil && *todo.AssigneeID == form.AssigneeID {
+		return todo, nil
+	}
+
+	before := *todo
+
+	todo.AssigneeID = &form.AssigneeID
+
+	err = s.saveAssignment(ctx, dB, user, &before, todo, entities.TodoActivityAssigned)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+func (s *todoController) UnassignTodo(ctx context.Context, dB db.DB, todoID int64) (*entities.Todo, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	if todo.AssigneeID == nil {
+		return todo, nil
+	}
+
+	before := *todo
+
+	err = s.saveAssignment(ctx, dB, user, &before, todo, entities.TodoActivityUnassigned)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+func (s *todoController) TodoActivities(ctx context.Context, dB db.DB, todoID int64) ([]*entities.TodoActivity, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return []*entities.TodoActivity{}, err
+	}
+
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessRead)
+	if err != nil {
+		return []*entities.TodoActivity{}, err
+	}
+
+	return s.todoActivityRepository.TodoActivities(ctx, dB, todo.ID)
+}
+
+// saveAssignment stores the todo's new assignee together with the activity
+// describing the change. Unassigning records the previous assignee.
+func (s *todoController) saveAssignment(ctx context.Context, dB db.DB, user *entities.User, before, todo *entities.Todo, action string) error {
+
+	activity := &entities.TodoActivity{
+		TodoID:     todo.ID,
+		ActorID:    user.ID,
+		Action:     action,
+		AssigneeID: todo.AssigneeID,
+	}
+
+	if action == entities.TodoActivityUnassigned {
+		todo.AssigneeID = nil
+	}
+
+	return dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.todoRepository.Save(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		err = s.todoActivityRepository.Save(ctx, operations, activity)
+		if err != nil {
+			return err
+		}
+
+		err = s.recordEvent(ctx, operations, user, entities.TodoEventUpdated, todo, entities.DiffTodos(before, todo))
+		if err != nil {
+			return err
+		}
+
+		s.cacheAfterCommit(operations, todo)
+
+		return nil
+	})
+}
+
+func (s *todoController) TodoHistory(ctx context.Context, dB db.DB, todoID int64) ([]*entities.TodoEvent, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return []*entities.TodoEvent{}, err
+	}
+
+	todo, err := s.todoForUser(ctx, dB, user, todoID, AccessRead)
+	if err != nil {
+		return []*entities.TodoEvent{}, err
+	}
+
+	return s.todoEventRepository.TodoEvents(ctx, dB, todo.ID)
+}
+
+// recordEvent writes the audit event with the same operations as the change
+// itself so the history cannot disagree with the todo. Updates that leave
+// every field untouched are not recorded.
+func (s *todoController) recordEvent(ctx context.Context, operations db.SQLOperations, user *entities.User, action string, todo *entities.Todo, changes map[string]*entities.FieldChange) error {
+
+	if action == entities.TodoEventUpdated && len(changes) == 0 {
+		return nil
+	}
+
+	event := &entities.TodoEvent{
+		TodoID:    todo.ID,
+		ActorID:   &user.ID,
+		Action:    action,
+		RequestID: contexthelper.RequestId(ctx),
+		UserAgent: contexthelper.UserAgent(ctx),
+		Changes:   changes,
+	}
+
+	return s.todoEventRepository.Save(ctx, operations, event)
+}
+
+func (s *todoController) cursorTodoList(todos []*entities.Todo, count int, filter *forms.Filter) (*entities.TodoList, error) {
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	hasMore := len(todos) > filter.Limit
+
+	if hasMore {
+		if backward {
+			todos = todos[1:]
+		} else {
+			todos = todos[:filter.Limit]
+		}
+	}
+
+	var nextCursor, prevCursor *string
+
+	if len(todos) > 0 {
+
+		if hasMore || backward {
+
+			cursor, err := s.encodeCursor(todos[len(todos)-1], filter.Sort, false)
+			if err != nil {
+				return &entities.TodoList{}, err
+			}
+
+			nextCursor = &cursor
+		}
+
+		if (hasMore && backward) || (!backward && filter.Cursor != nil) {
+
+			cursor, err := s.encodeCursor(todos[0], filter.Sort, true)
+			if err != nil {
+				return &entities.TodoList{}, err
+			}
+
+			prevCursor = &cursor
+		}
+	}
+
+	todoList := &entities.TodoList{
+		Todos:      todos,
+		Pagination: entities.NewCursorPagination(count, filter.Limit, nextCursor, prevCursor),
+	}
+
+	return todoList, nil
+}
+
+func (s *todoController) encodeCursor(todo *entities.Todo, sortFields []forms.SortField, backward bool) (string, error) {
+
+	cursor := &forms.Cursor{
+		Backward: backward,
+		ID:       todo.ID,
+		Sort:     forms.SortKey(sortFields),
+		Values:   make([]string, 0, len(sortFields)),
+	}
+
+	for _, sortField := range sortFields {
+
+		var value string
+
+		switch sortField.Field {
+		case forms.SortFieldCompletedAt:
+			value = formatCursorTime(todo.CompletedAt)
+		case forms.SortFieldCreatedAt:
+			value = formatCursorTime(&todo.CreatedAt)
+		case forms.SortFieldDueAt:
+			value = formatCursorTime(todo.DueAt)
+		case forms.SortFieldPriority:
+			value = strconv.Itoa(int(todo.Priority))
+		case forms.SortFieldTitle:
+			value = todo.Title
+		case forms.SortFieldUpdatedAt:
+			value = formatCursorTime(&todo.UpdatedAt)
+		default:
+			return "", fmt.Errorf("invalid sort field %v", sortField.Field)
+		}
+
+		cursor.Values = append(cursor.Values, value)
+	}
+
+	return s.cursorCodec.Encode(cursor)
+}
+
+func (s *todoController) todoForUser(ctx context.Context, dB db.DB, user *entities.User, todoID int64, access Access) (*entities.Todo, error) {
+
+	var todo *entities.Todo
+
+	err := s.cacheController.GetOrLoad(s.generateCacheKey(todoID), &todo, func() (any, error) {
+		return s.todoRepository.TodoByID(ctx, dB, todoID)
+	})
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	// todos cached before they had a status only know if they are done
+	todo.SyncStatus()
+
+	err = s.accessController.AuthorizeTodo(ctx, dB, user, todo, access)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	if access != AccessRead {
+
+		err = checkIfMatch(ctx, todo)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+	}
+
+	return todo, nil
+}
+
+// createNextOccurrence adds the todo that follows a completed recurring todo,
+// carrying over its tags and checklist. It returns nil when the todo does not
+// recur or its series has ended.
+func (s *todoController) createNextOccurrence(ctx context.Context, operations db.SQLOperations, todo *entities.Todo) (*entities.Todo, error) {
+
+	if todo.RecurrenceRule == nil || todo.DueAt == nil {
+		return nil, nil
+	}
+
+	// a todo that was reopened and completed again already has its next
+	// occurrence
+	series, err := s.todoRepository.Todos(ctx, operations, &forms.Filter{IncludeArchived: true, SeriesID: *todo.SeriesID})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, seriesTodo := range series {
+		if seriesTodo.Occurrence > todo.Occurrence {
+			return nil, nil
+		}
+	}
+
+	rule, err := utils.ParseRecurrenceRule(*todo.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	seriesStartAt := *todo.DueAt
+	if todo.SeriesStartAt != nil {
+		seriesStartAt = *todo.SeriesStartAt
+	}
+
+	dueAt, ok := rule.Next(seriesStartAt.In(todo.DueAt.Location()), *todo.DueAt, todo.Occurrence)
+	if !ok {
+		return nil, nil
+	}
+
+	nextTodo := &entities.Todo{
+		OwnerID:        todo.OwnerID,
+		ProjectID:      todo.ProjectID,
+		WorkspaceID:    todo.WorkspaceID,
+		AssigneeID:     todo.AssigneeID,
+		Title:          todo.Title,
+		Description:    todo.Description,
+		DueAt:          &dueAt,
+		Priority:       todo.Priority,
+		Tags:           todo.Tags,
+		RecurrenceRule: todo.RecurrenceRule,
+		SeriesID:       todo.SeriesID,
+		SeriesStartAt:  &seriesStartAt,
+		Occurrence:     todo.Occurrence + 1,
+	}
+
+	err = s.todoRepository.Save(ctx, operations, nextTodo)
+	if err != nil {
+		return nil, err
+	}
+
+	tagIDs := make([]int64, 0, len(todo.Tags))
+	for _, tag := range todo.Tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	err = s.tagRepository.AttachTags(ctx, operations, nextTodo.ID, tagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	todoItems, err := s.todoItemRepository.TodoItems(ctx, operations, todo.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, todoItem := range todoItems {
+
+		err = s.todoItemRepository.Save(ctx, operations, &entities.TodoItem{
+			TodoID: nextTodo.ID,
+			Title:  todoItem.Title,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nextTodo.SetItemCounts(entities.TodoItemCounts{Total: len(todoItems)})
+
+	return nextTodo, nil
+}
+
+// validateAssignee only accepts members of the todo's workspace, or the
+// owner for a personal todo.
+func (s *todoController) validateAssignee(ctx context.Context, dB db.DB, todo *entities.Todo, assigneeID int64) error {
+
+	if todo.WorkspaceID == nil {
+
+		if assigneeID != todo.OwnerID {
+			return fmt.Errorf("personal todos can only be assigned to their owner")
+		}
+
+		return nil
+	}
+
+	_, err := s.workspaceRepository.MemberByUser(ctx, dB, *todo.WorkspaceID, assigneeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("assignee is not a member of this workspace")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *todoController) validateProject(ctx context.Context, dB db.DB, user *entities.User, todo *entities.Todo, projectID int64) error {
+
+	if todo.WorkspaceID != nil {
+		return fmt.Errorf("workspace todos cannot belong to a personal project")
+	}
+
+	project, err := s.projectRepository.ProjectByID(ctx, dB, projectID)
+	if err != nil {
+		return err
+	}
+
+	if project.OwnerID != user.ID {
+		return apperror.NewDatabaseError(sql.ErrNoRows)
+	}
+
+	if project.Archived() {
+		return fmt.Errorf("cannot add todos to an archived project")
+	}
+
+	return nil
+}
+
+// tagsForUser loads the tags being attached to a todo and rejects any that
+// do not exist or belong to someone else.
+func (s *todoController) tagsForUser(ctx context.Context, dB db.DB, user *entities.User, tagIDs []int64) ([]*entities.Tag, error) {
+
+	tagIDs = slices.Clone(tagIDs)
+	slices.Sort(tagIDs)
+	tagIDs = slices.Compact(tagIDs)
+
+	tags, err := s.tagRepository.TagsByIDs(ctx, dB, user.ID, tagIDs)
+	if err != nil {
+		return []*entities.Tag{}, err
+	}
+
+	if len(tags) != len(tagIDs) {
+		return []*entities.Tag{}, apperror.NewDatabaseError(sql.ErrNoRows)
+	}
+
+	return tags, nil
+}
+
+// setRecurrenceRule stores the rule in its normalized form and starts a new
+// run of occurrences from the todo's due date.
+func setRecurrenceRule(todo *entities.Todo, value string) error {
+
+	if todo.DueAt == nil {
+		return errRecurrenceWithoutDueAt
+	}
+
+	rule, err := utils.ParseRecurrenceRule(value)
+	if err != nil {
+		return err
+	}
+
+	normalizedRule := rule.String()
+	seriesStartAt := *todo.DueAt
+
+	todo.RecurrenceRule = &normalizedRule
+	todo.SeriesStartAt = &seriesStartAt
+	todo.Occurrence = 1
+
+	return nil
+}
+
+func validateDueAt(dueAt time.Time) error {
+
+	if dueAt.IsZero() {
+		return fmt.Errorf("due date cannot be empty")
+	}
+
+	if dueAt.Before(time.Now().Add(-dueAtClockSkew)) {
+		return fmt.Errorf("due date cannot be in the past")
+	}
+
+	return nil
+}
+
+func formatCursorTime(t *time.Time) string {
+	if t == nil {
+		// matches the COALESCE used when sorting by nullable timestamps
+		return "infinity"
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func (s *todoController) generateCacheKey(todoID int64) string {
+	return fmt.Sprintf(todoKeyPrefix, todoID)
+}
+
+// cacheAfterCommit writes todo through to the cache once operations commits
+// and retires the listings it appears in, and those of before when a change
+// moved it out of them. Nothing is cached for a transaction that rolls back.
+func (s *todoController) cacheAfterCommit(operations db.SQLOperations, todo *entities.Todo, before ...*entities.Todo) {
+
+	db.AfterCommit(operations, func() {
+
+		err := s.cacheController.CacheValue(s.generateCacheKey(todo.ID), todo)
+		logCacheError("set", err)
+
+		err = invalidateTodoLists(s.cacheController, todoListScopes(append(before, todo)...))
+		logCacheError("invalidate", err)
+	})
+}
+
+// uncacheAfterCommit drops todo and the listings it appears in from the
+// cache once operations commits.
+func (s *todoController) uncacheAfterCommit(operations db.SQLOperations, todo *entities.Todo) {
+
+	db.AfterCommit(operations, func() {
+
+		err := s.cacheController.RemoveFromCache(s.generateCacheKey(todo.ID))
+		logCacheError("del", err)
+
+		err = invalidateTodoLists(s.cacheController, todoListScopes(todo))
+		logCacheError("invalidate", err)
+	})
+}
+
+// todoListCacheKey names a cached page of todos after the current generation
+// of its scope, so bumping the generation retires every page at once and the
+// old ones are left to expire.
+func (s *todoController) todoListCacheKey(scope string, filter *forms.Filter) (string, error) {
+
+	generation, err := s.cacheController.Counter(fmt.Sprintf(todoListGenerationPrefix, scope))
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(filter.Normalized())
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+
+	return fmt.Sprintf(todoListKeyPrefix, scope, generation, sum[:16]), nil
+}
+
+// todoListScope picks the generation that guards cached pages of a listing.
+// Trash listings also change when the trash is purged, listings across
+// workspaces when memberships change and overdue listings as time passes,
+// none of which bump a generation, so those are not cached.
+func todoListScope(filter *forms.Filter) (string, bool) {
+
+	switch {
+	case filter.Deleted, filter.IncludeWorkspaces, filter.Overdue:
+		return "", false
+	case filter.ProjectID > 0:
+		return fmt.Sprintf(todoListProjectScope, filter.ProjectID), true
+	case filter.WorkspaceID > 0:
+		return fmt.Sprintf(todoListWorkspaceScope, filter.WorkspaceID), true
+	default:
+		return fmt.Sprintf(todoListOwnerScope, filter.OwnerID), true
+	}
+}
+
+// todoListScopes lists the generations of every listing the todos appear in.
+// Callers pass a todo as it was before a change as well as after when the
+// change can move it to another project or workspace.
+func todoListScopes(todos ...*entities.Todo) []string {
+
+	scopes := make([]string, 0, 2*len(todos))
+
+	for _, todo := range todos {
+
+		if todo.WorkspaceID != nil {
+			scopes = append(scopes, fmt.Sprintf(todoListWorkspaceScope, *todo.WorkspaceID))
+		} else {
+			scopes = append(scopes, fmt.Sprintf(todoListOwnerScope, todo.OwnerID))
+		}
+
+		if todo.ProjectID != nil {
+			scopes = append(scopes, fmt.Sprintf(todoListProjectScope, *todo.ProjectID))
+		}
+	}
+
+	return scopes
+}
+
+// invalidateTodoLists bumps the generations of the scopes, which retires
+// every cached page of their listings.
+func invalidateTodoLists(cacheController CacheController, scopes []string) error {
+
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+
+	for _, scope := range slices.Compact(scopes) {
+
+		_, err := cacheController.Increment(fmt.Sprintf(todoListGenerationPrefix, scope))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// withDueAtAfterPriority breaks ties between todos of equal priority by due
+// date, soonest first, unless the caller already ordered by due date.
+func withDueAtAfterPriority(sortFields []forms.SortField) []forms.SortField {
+
+	priorityIndex := slices.IndexFunc(sortFields, func(sortField forms.SortField) bool {
+		return sortField.Field == forms.SortFieldPriority
+	})
+	if priorityIndex < 0 {
+		return sortFields
+	}
+
+	hasDueAt := slices.ContainsFunc(sortFields, func(sortField forms.SortField) bool {
+		return sortField.Field == forms.SortFieldDueAt
+	})
+	if hasDueAt {
+		return sortFields
+	}
+
+	return slices.Insert(slices.Clone(sortFields), priorityIndex+1, forms.SortField{Field: forms.SortFieldDueAt})
+}
+
+// checkIfMatch refuses a change when the client sent an If-Match header for
+// a version of the todo other than the current one.
+func checkIfMatch(ctx context.Context, todo *entities.Todo) error {
+
+	ifMatch := contexthelper.IfMatch(ctx)
+
+	if ifMatch != "" && !utils.MatchETag(ifMatch, todo.ETag()) {
+		return apperror.NewPreconditionFailedError(errors.New("todo has been changed since it was fetched"))
+	}
+
+	return nil
 }
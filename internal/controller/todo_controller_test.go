@@ -2,14 +2,19 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/ernestngugi/todo/internal/apperror"
 	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
 	"github.com/ernestngugi/todo/internal/forms"
 	"github.com/ernestngugi/todo/internal/mocks"
 	"github.com/ernestngugi/todo/internal/repository"
 	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
 	. "github.com/smartystreets/goconvey/convey"
 	"syreclabs.com/go/faker"
 )
@@ -27,6 +32,11 @@ func TestTodoController(t *testing.T) {
 
 		todoController := NewTestTodoController(redisManager)
 
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
 		Convey("can create a todo", func() {
 
 			form := &forms.CreateTodoForm{
@@ -37,6 +47,7 @@ func TestTodoController(t *testing.T) {
 			todo, err := todoController.CreateTodo(ctx, dB, form)
 			So(err, ShouldBeNil)
 
+			So(todo.OwnerID, ShouldEqual, user.ID)
 			So(todo.Title, ShouldEqual, form.Title)
 			So(todo.Description, ShouldEqual, form.Description)
 			So(todo.Completed, ShouldBeFalse)
@@ -47,10 +58,10 @@ func TestTodoController(t *testing.T) {
 
 		Convey("can complete a todo", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
-			completedTodo, err := todoController.CompleteTodo(ctx, dB, todo.ID)
+			completedTodo, err := todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
 			So(err, ShouldBeNil)
 
 			So(completedTodo.Completed, ShouldBeTrue)
@@ -60,7 +71,7 @@ func TestTodoController(t *testing.T) {
 
 		Convey("cannot mark an already completed todo as complete", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			timeNow := time.Now()
@@ -70,7 +81,7 @@ func TestTodoController(t *testing.T) {
 			err = todoController.todoRepository.Save(ctx, dB, todo)
 			So(err, ShouldBeNil)
 
-			_, err = todoController.CompleteTodo(ctx, dB, todo.ID)
+			_, err = todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
 			So(err, ShouldNotBeNil)
 
 			So(err.Error(), ShouldEqual, "todo has been marked as complete")
@@ -78,7 +89,7 @@ func TestTodoController(t *testing.T) {
 
 		Convey("can update a todo", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			title := "newtest"
@@ -96,7 +107,7 @@ func TestTodoController(t *testing.T) {
 
 		Convey("can delete a todo", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			err = todoController.DeleteTodo(ctx, dB, todo.ID)
@@ -108,30 +119,34 @@ func TestTodoController(t *testing.T) {
 			So(err.Error(), ShouldContainSubstring, "sql: no rows in result set")
 		})
 
-		Convey("cannot delete a completed todo", func() {
+		Convey("can delete a done todo", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
-			timeNow := time.Now()
-			todo.Completed = true
-			todo.CompletedAt = &timeNow
+			todo.SetStatus(entities.TodoStatusDone, time.Now())
 
 			err = todoController.todoRepository.Save(ctx, dB, todo)
 			So(err, ShouldBeNil)
 
 			err = todoController.DeleteTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.todoRepository.TodoByID(ctx, dB, todo.ID)
 			So(err, ShouldNotBeNil)
 
-			So(err.Error(), ShouldEqual, "cannot a todo that has been completed")
+			deletedTodo, err := todoController.todoRepository.DeletedTodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(deletedTodo.Status, ShouldEqual, entities.TodoStatusDone)
 		})
 
 		Convey("can list todos", func() {
 
-			todo1, err := repository.CreateTodo(ctx, dB)
+			todo1, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
-			todo2, err := repository.CreateTodo(ctx, dB)
+			todo2, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			todos, err := todoController.Todos(ctx, dB, &forms.Filter{})
@@ -149,7 +164,7 @@ func TestTodoController(t *testing.T) {
 
 		Convey("can get todo by id", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			foundTodo, err := todoController.TodoByID(ctx, dB, todo.ID)
@@ -163,5 +178,606 @@ func TestTodoController(t *testing.T) {
 			So(foundTodo.CreatedAt, ShouldNotBeZeroValue)
 			So(foundTodo.UpdatedAt, ShouldNotBeZeroValue)
 		})
+
+		Convey("cannot get a todo belonging to another user", func() {
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			todo, err := repository.CreateTodo(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldContainSubstring, "sql: no rows in result set")
+		})
+
+		Convey("cannot update a todo belonging to another user", func() {
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			todo, err := repository.CreateTodo(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			title := "newtest"
+
+			_, err = todoController.UpdateTodo(ctx, dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldNotBeNil)
+
+			foundTodo, err := todoController.todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.Title, ShouldEqual, todo.Title)
+		})
+
+		Convey("can only list todos of the authenticated user", func() {
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			_, err = repository.CreateTodo(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			todos, err := todoController.Todos(ctx, dB, &forms.Filter{})
+			So(err, ShouldBeNil)
+
+			So(len(todos.Todos), ShouldEqual, 1)
+			So(todos.Todos[0].ID, ShouldEqual, todo.ID)
+			So(todos.Pagination.Count, ShouldEqual, 1)
+		})
+
+		Convey("cannot list todos without an authenticated user", func() {
+
+			_, err := todoController.Todos(context.Background(), dB, &forms.Filter{})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "user not authenticated")
+		})
+
+		Convey("can page through todos with a cursor", func() {
+
+			todos := make([]*entities.Todo, 0, 5)
+
+			for i := 0; i < 5; i++ {
+				todo, err := repository.CreateTodo(ctx, dB, user)
+				So(err, ShouldBeNil)
+
+				todos = append(todos, todo)
+			}
+
+			sort := []forms.SortField{{Field: forms.SortFieldCreatedAt, Descending: true}}
+
+			firstPage, err := todoController.Todos(ctx, dB, &forms.Filter{Limit: 2, Sort: sort})
+			So(err, ShouldBeNil)
+
+			So(len(firstPage.Todos), ShouldEqual, 2)
+			So(firstPage.Todos[0].ID, ShouldEqual, todos[4].ID)
+			So(firstPage.Todos[1].ID, ShouldEqual, todos[3].ID)
+			So(firstPage.Pagination.Count, ShouldEqual, 5)
+			So(firstPage.Pagination.NextCursor, ShouldNotBeNil)
+			So(firstPage.Pagination.PrevCursor, ShouldBeNil)
+
+			secondPage, err := todoController.Todos(ctx, dB, &forms.Filter{Limit: 2, Sort: sort, CursorToken: *firstPage.Pagination.NextCursor})
+			So(err, ShouldBeNil)
+
+			So(len(secondPage.Todos), ShouldEqual, 2)
+			So(secondPage.Todos[0].ID, ShouldEqual, todos[2].ID)
+			So(secondPage.Todos[1].ID, ShouldEqual, todos[1].ID)
+			So(secondPage.Pagination.PrevCursor, ShouldNotBeNil)
+
+			lastPage, err := todoController.Todos(ctx, dB, &forms.Filter{Limit: 2, Sort: sort, CursorToken: *secondPage.Pagination.NextCursor})
+			So(err, ShouldBeNil)
+
+			So(len(lastPage.Todos), ShouldEqual, 1)
+			So(lastPage.Todos[0].ID, ShouldEqual, todos[0].ID)
+			So(lastPage.Pagination.NextCursor, ShouldBeNil)
+
+			previousPage, err := todoController.Todos(ctx, dB, &forms.Filter{Limit: 2, Sort: sort, CursorToken: *secondPage.Pagination.PrevCursor})
+			So(err, ShouldBeNil)
+
+			So(len(previousPage.Todos), ShouldEqual, 2)
+			So(previousPage.Todos[0].ID, ShouldEqual, todos[4].ID)
+			So(previousPage.Todos[1].ID, ShouldEqual, todos[3].ID)
+			So(previousPage.Pagination.PrevCursor, ShouldBeNil)
+			So(previousPage.Pagination.NextCursor, ShouldNotBeNil)
+		})
+
+		Convey("cannot use a cursor with a different sort", func() {
+
+			for i := 0; i < 3; i++ {
+				_, err := repository.CreateTodo(ctx, dB, user)
+				So(err, ShouldBeNil)
+			}
+
+			firstPage, err := todoController.Todos(ctx, dB, &forms.Filter{Limit: 1})
+			So(err, ShouldBeNil)
+
+			_, err = todoController.Todos(ctx, dB, &forms.Filter{
+				Limit:       1,
+				Sort:        []forms.SortField{{Field: forms.SortFieldTitle}},
+				CursorToken: *firstPage.Pagination.NextCursor,
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can create a todo with a due date", func() {
+
+			dueAt := time.Now().Add(24 * time.Hour)
+
+			form := &forms.CreateTodoForm{
+				Title:       "test",
+				Description: faker.Lorem().Paragraph(1),
+				DueAt:       &dueAt,
+			}
+
+			todo, err := todoController.CreateTodo(ctx, dB, form)
+			So(err, ShouldBeNil)
+
+			So(todo.DueAt.Equal(dueAt), ShouldBeTrue)
+		})
+
+		Convey("cannot create a todo due in the past", func() {
+
+			dueAt := time.Now().Add(-24 * time.Hour)
+
+			form := &forms.CreateTodoForm{
+				Title:       "test",
+				Description: faker.Lorem().Paragraph(1),
+				DueAt:       &dueAt,
+			}
+
+			_, err := todoController.CreateTodo(ctx, dB, form)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "due date cannot be in the past")
+		})
+
+		Convey("can update and clear the due date of a todo", func() {
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			dueAt := time.Now().Add(time.Hour)
+
+			updatedTodo, err := todoController.UpdateTodo(ctx, dB, todo.ID, &forms.UpdateTodoForm{DueAt: &dueAt})
+			So(err, ShouldBeNil)
+
+			So(updatedTodo.DueAt, ShouldNotBeNil)
+
+			updatedTodo, err = todoController.UpdateTodo(ctx, dB, todo.ID, &forms.UpdateTodoForm{ClearDueAt: true})
+			So(err, ShouldBeNil)
+
+			So(updatedTodo.DueAt, ShouldBeNil)
+
+			foundTodo, err := todoController.todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.DueAt, ShouldBeNil)
+		})
+
+		Convey("can list todos due today in the caller's time zone", func() {
+
+			location, err := time.LoadLocation("Pacific/Kiritimati")
+			So(err, ShouldBeNil)
+
+			now := time.Now().In(location)
+			endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 0, 0, location)
+			tomorrow := endOfDay.Add(time.Hour)
+
+			dueToday, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			dueToday.DueAt = &endOfDay
+
+			err = todoController.todoRepository.Save(ctx, dB, dueToday)
+			So(err, ShouldBeNil)
+
+			dueTomorrow, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			dueTomorrow.DueAt = &tomorrow
+
+			err = todoController.todoRepository.Save(ctx, dB, dueTomorrow)
+			So(err, ShouldBeNil)
+
+			todos, err := todoController.TodosDueToday(ctx, dB, location, &forms.Filter{})
+			So(err, ShouldBeNil)
+
+			So(len(todos.Todos), ShouldEqual, 1)
+			So(todos.Todos[0].ID, ShouldEqual, dueToday.ID)
+		})
+
+		Convey("can create a todo with a priority", func() {
+
+			form := &forms.CreateTodoForm{
+				Title:       "test",
+				Description: faker.Lorem().Paragraph(1),
+				Priority:    "high",
+			}
+
+			todo, err := todoController.CreateTodo(ctx, dB, form)
+			So(err, ShouldBeNil)
+
+			So(todo.Priority, ShouldEqual, entities.PriorityHigh)
+
+			foundTodo, err := todoController.todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.Priority, ShouldEqual, entities.PriorityHigh)
+		})
+
+		Convey("cannot create a todo with an unknown priority", func() {
+
+			form := &forms.CreateTodoForm{
+				Title:       "test",
+				Description: faker.Lorem().Paragraph(1),
+				Priority:    "critical",
+			}
+
+			_, err := todoController.CreateTodo(ctx, dB, form)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can filter and sort todos by priority then due date", func() {
+
+			soon := time.Now().Add(time.Hour)
+			later := time.Now().Add(48 * time.Hour)
+
+			todos := make([]*entities.Todo, 0)
+
+			for _, values := range []struct {
+				dueAt    *time.Time
+				priority entities.Priority
+			}{
+				{&later, entities.PriorityUrgent},
+				{&soon, entities.PriorityUrgent},
+				{nil, entities.PriorityHigh},
+				{&soon, entities.PriorityLow},
+			} {
+
+				todo, err := repository.CreateTodo(ctx, dB, user)
+				So(err, ShouldBeNil)
+
+				todo.DueAt = values.dueAt
+				todo.Priority = values.priority
+
+				err = todoController.todoRepository.Save(ctx, dB, todo)
+				So(err, ShouldBeNil)
+
+				todos = append(todos, todo)
+			}
+
+			filter := &forms.Filter{
+				Priorities: []entities.Priority{entities.PriorityHigh, entities.PriorityUrgent},
+				Sort:       []forms.SortField{{Field: forms.SortFieldPriority, Descending: true}},
+			}
+
+			todoList, err := todoController.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(todoList.Todos), ShouldEqual, 3)
+			So(todoList.Todos[0].ID, ShouldEqual, todos[1].ID)
+			So(todoList.Todos[1].ID, ShouldEqual, todos[0].ID)
+			So(todoList.Todos[2].ID, ShouldEqual, todos[2].ID)
+		})
+
+		Convey("cannot create a recurring todo without a due date", func() {
+
+			form := &forms.CreateTodoForm{
+				Title:          "test",
+				Description:    faker.Lorem().Paragraph(1),
+				RecurrenceRule: "FREQ=WEEKLY",
+			}
+
+			_, err := todoController.CreateTodo(ctx, dB, form)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "recurring todos need a due date")
+		})
+
+		Convey("creates the next occurrence when completing a recurring todo", func() {
+
+			tag, err := repository.CreateTag(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			dueAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{
+				Title:          "test",
+				Description:    faker.Lorem().Paragraph(1),
+				DueAt:          &dueAt,
+				RecurrenceRule: "freq=weekly;count=2",
+				TagIDs:         []int64{tag.ID},
+			})
+			So(err, ShouldBeNil)
+
+			So(*todo.RecurrenceRule, ShouldEqual, "FREQ=WEEKLY;COUNT=2")
+
+			_, err = todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
+			So(err, ShouldBeNil)
+
+			series, err := todoController.Todos(ctx, dB, &forms.Filter{SeriesID: todo.ID})
+			So(err, ShouldBeNil)
+
+			So(len(series.Todos), ShouldEqual, 2)
+			So(series.Todos[0].ID, ShouldEqual, todo.ID)
+			So(series.Todos[0].Completed, ShouldBeTrue)
+
+			nextTodo := series.Todos[1]
+
+			So(nextTodo.Completed, ShouldBeFalse)
+			So(nextTodo.Occurrence, ShouldEqual, 2)
+			So(nextTodo.DueAt.Equal(dueAt.AddDate(0, 0, 7)), ShouldBeTrue)
+			So(len(nextTodo.Tags), ShouldEqual, 1)
+
+			_, err = todoController.CompleteTodo(ctx, dB, nextTodo.ID, &forms.CompleteTodoForm{})
+			So(err, ShouldBeNil)
+
+			series, err = todoController.Todos(ctx, dB, &forms.Filter{SeriesID: todo.ID})
+			So(err, ShouldBeNil)
+
+			So(len(series.Todos), ShouldEqual, 2)
+		})
+
+		Convey("records the history of a todo with the request that changed it", func() {
+
+			ctx := contexthelper.WithUserAgent(contexthelper.WithRequestId(ctx, "req-history"), "todo-test")
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{Title: "history"})
+			So(err, ShouldBeNil)
+
+			title := "renamed"
+
+			_, err = todoController.UpdateTodo(ctx, dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldBeNil)
+
+			_, err = todoController.UpdateTodo(ctx, dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldBeNil)
+
+			_, err = todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
+			So(err, ShouldBeNil)
+
+			events, err := todoController.TodoHistory(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(len(events), ShouldEqual, 3)
+			So(events[0].Action, ShouldEqual, entities.TodoEventCreated)
+			So(events[1].Action, ShouldEqual, entities.TodoEventUpdated)
+			So(events[2].Action, ShouldEqual, entities.TodoEventCompleted)
+
+			So(*events[1].ActorID, ShouldEqual, user.ID)
+			So(events[1].RequestID, ShouldEqual, "req-history")
+			So(events[1].UserAgent, ShouldEqual, "todo-test")
+			So(len(events[1].Changes), ShouldEqual, 1)
+			So(events[1].Changes["title"].From, ShouldEqual, "history")
+			So(events[1].Changes["title"].To, ShouldEqual, "renamed")
+
+			So(events[2].Changes["completed"].To, ShouldEqual, true)
+		})
+
+		Convey("keeps the history of a deleted todo", func() {
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{Title: "history"})
+			So(err, ShouldBeNil)
+
+			err = todoController.DeleteTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			events, err := todoController.todoEventRepository.TodoEvents(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(len(events), ShouldEqual, 2)
+			So(events[1].Action, ShouldEqual, entities.TodoEventDeleted)
+		})
+
+		Convey("can restore a deleted todo from the trash", func() {
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{Title: "trash"})
+			So(err, ShouldBeNil)
+
+			err = todoController.DeleteTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldNotBeNil)
+
+			trash, err := todoController.Trash(ctx, dB, &forms.Filter{})
+			So(err, ShouldBeNil)
+
+			So(len(trash.Todos), ShouldEqual, 1)
+			So(trash.Todos[0].DeletedAt, ShouldNotBeNil)
+
+			restoredTodo, err := todoController.RestoreTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(restoredTodo.DeletedAt, ShouldBeNil)
+
+			_, err = todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			events, err := todoController.TodoHistory(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(events[len(events)-1].Action, ShouldEqual, entities.TodoEventRestored)
+		})
+
+		Convey("cannot restore a todo that is not in the trash", func() {
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.RestoreTodo(ctx, dB, todo.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "sql: no rows in result set")
+		})
+
+		Convey("can reopen a completed todo", func() {
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			completedTodo, err := todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
+			So(err, ShouldBeNil)
+
+			So(completedTodo.Status, ShouldEqual, entities.TodoStatusDone)
+
+			reopenedTodo, err := todoController.TransitionTodo(ctx, dB, todo.ID, &forms.TransitionTodoForm{Status: entities.TodoStatusOpen})
+			So(err, ShouldBeNil)
+
+			So(reopenedTodo.Status, ShouldEqual, entities.TodoStatusOpen)
+			So(reopenedTodo.Completed, ShouldBeFalse)
+			So(reopenedTodo.CompletedAt, ShouldBeNil)
+
+			foundTodo, err := todoController.todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.Status, ShouldEqual, entities.TodoStatusOpen)
+			So(foundTodo.Completed, ShouldBeFalse)
+		})
+
+		Convey("only allows transitions from the transition table", func() {
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.TransitionTodo(ctx, dB, todo.ID, &forms.TransitionTodoForm{Status: entities.TodoStatusBlocked})
+			So(err, ShouldBeNil)
+
+			_, err = todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "cannot move a todo from blocked to done")
+
+			_, err = todoController.TransitionTodo(ctx, dB, todo.ID, &forms.TransitionTodoForm{Status: entities.TodoStatusBlocked})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "todo is already blocked")
+
+			_, err = todoController.TransitionTodo(ctx, dB, todo.ID, &forms.TransitionTodoForm{Status: "waiting"})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can filter todos by status", func() {
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.TransitionTodo(ctx, dB, todo.ID, &forms.TransitionTodoForm{Status: entities.TodoStatusCancelled})
+			So(err, ShouldBeNil)
+
+			todoList, err := todoController.Todos(ctx, dB, &forms.Filter{Statuses: []entities.TodoStatus{entities.TodoStatusCancelled}})
+			So(err, ShouldBeNil)
+
+			So(len(todoList.Todos), ShouldEqual, 1)
+			So(todoList.Todos[0].ID, ShouldEqual, todo.ID)
+			So(todoList.Todos[0].Completed, ShouldBeFalse)
+		})
+
+		Convey("only changes a todo when If-Match names its current version", func() {
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			title := "matched"
+
+			staleCtx := contexthelper.WithIfMatch(ctx, `"0"`)
+
+			_, err = todoController.UpdateTodo(staleCtx, dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusPreconditionFailed)
+
+			err = todoController.DeleteTodo(staleCtx, dB, todo.ID)
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusPreconditionFailed)
+
+			updatedTodo, err := todoController.UpdateTodo(contexthelper.WithIfMatch(ctx, todo.ETag()), dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldBeNil)
+
+			So(updatedTodo.Title, ShouldEqual, title)
+			So(updatedTodo.Version, ShouldEqual, todo.Version+1)
+		})
+
+		Convey("serves cached todo lists until a todo in them changes", func() {
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{Title: "test", Description: "test"})
+			So(err, ShouldBeNil)
+
+			todoList, err := todoController.Todos(ctx, dB, &forms.Filter{Page: 1, Per: 20})
+			So(err, ShouldBeNil)
+
+			So(len(todoList.Todos), ShouldEqual, 1)
+
+			// written behind the controller's back, so nothing is invalidated
+			_, err = repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			todoList, err = todoController.Todos(ctx, dB, &forms.Filter{Page: 1, Per: 20})
+			So(err, ShouldBeNil)
+
+			So(len(todoList.Todos), ShouldEqual, 1)
+			So(todoList.Pagination.Count, ShouldEqual, 1)
+
+			_, err = todoController.TransitionTodo(ctx, dB, todo.ID, &forms.TransitionTodoForm{Status: entities.TodoStatusInProgress})
+			So(err, ShouldBeNil)
+
+			todoList, err = todoController.Todos(ctx, dB, &forms.Filter{Page: 1, Per: 20})
+			So(err, ShouldBeNil)
+
+			So(len(todoList.Todos), ShouldEqual, 2)
+			So(todoList.Pagination.Count, ShouldEqual, 2)
+		})
+
+		Convey("only caches a todo once its transaction commits", func() {
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+				todoController.cacheAfterCommit(operations, todo)
+				return errors.New("rolled back")
+			})
+			So(err, ShouldNotBeNil)
+
+			exists, err := todoController.cacheController.Exists(todoController.generateCacheKey(todo.ID))
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+
+			err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+				todoController.cacheAfterCommit(operations, todo)
+				return nil
+			})
+			So(err, ShouldBeNil)
+
+			exists, err = todoController.cacheController.Exists(todoController.generateCacheKey(todo.ID))
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+		})
+
+		Convey("keeps serving todos while the cache is down", func() {
+
+			redisManager.SetError(errors.New("connection refused"))
+
+			todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{Title: "test", Description: "test"})
+			So(err, ShouldBeNil)
+
+			foundTodo, err := todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+			So(foundTodo.ID, ShouldEqual, todo.ID)
+
+			todoList, err := todoController.Todos(ctx, dB, &forms.Filter{Page: 1, Per: 20})
+			So(err, ShouldBeNil)
+			So(len(todoList.Todos), ShouldEqual, 1)
+		})
 	}))
 }
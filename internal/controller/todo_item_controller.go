@@ -0,0 +1,283 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+)
+
+type (
+	TodoItemController interface {
+		CreateTodoItem(ctx context.Context, dB db.DB, todoID int64, form *forms.CreateTodoItemForm) (*entities.Todo, *entities.TodoItem, error)
+		DeleteTodoItem(ctx context.Context, dB db.DB, todoID, todoItemID int64) (*entities.Todo, error)
+		ReorderTodoItems(ctx context.Context, dB db.DB, todoID int64, form *forms.ReorderTodoItemsForm) (*entities.Todo, []*entities.TodoItem, error)
+		TodoItems(ctx context.Context, dB db.DB, todoID int64) ([]*entities.TodoItem, error)
+		ToggleTodoItem(ctx context.Context, dB db.DB, todoID, todoItemID int64) (*entities.Todo, *entities.TodoItem, error)
+	}
+
+	todoItemController struct {
+		accessController   AccessController
+		cacheController    CacheController
+		todoItemRepository repository.TodoItemRepository
+		todoRepository     repository.TodoRepository
+	}
+)
+
+func NewTodoItemController(
+	accessController AccessController,
+	cacheController CacheController,
+	todoItemRepository repository.TodoItemRepository,
+	todoRepository repository.TodoRepository,
+) TodoItemController {
+	return &todoItemController{
+		accessController:   accessController,
+		cacheController:    cacheController,
+		todoItemRepository: todoItemRepository,
+		todoRepository:     todoRepository,
+	}
+}
+
+func NewTestTodoItemController(
+	redisProvider providers.Redis,
+) *todoItemController {
+	return &todoItemController{
+		accessController:   NewTestAccessController(),
+		cacheController:    NewTestCacheController(redisProvider),
+		todoItemRepository: repository.NewTodoItemRepository(),
+		todoRepository:     repository.NewTodoRepository(),
+	}
+}
+
+func (s *todoItemController) TodoItems(ctx context.Context, dB db.DB, todoID int64) ([]*entities.TodoItem, error) {
+
+	todo, err := s.todoForUser(ctx, dB, todoID, AccessRead)
+	if err != nil {
+		return []*entities.TodoItem{}, err
+	}
+
+	return s.todoItemRepository.TodoItems(ctx, dB, todo.ID)
+}
+
+func (s *todoItemController) CreateTodoItem(ctx context.Context, dB db.DB, todoID int64, form *forms.CreateTodoItemForm) (*entities.Todo, *entities.TodoItem, error) {
+
+	todo, err := s.todoForUser(ctx, dB, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, &entities.TodoItem{}, err
+	}
+
+	title := strings.TrimSpace(form.Title)
+
+	err = utils.ValidateSingleName(title)
+	if err != nil {
+		return &entities.Todo{}, &entities.TodoItem{}, err
+	}
+
+	todoItem := &entities.TodoItem{
+		TodoID: todo.ID,
+		Title:  title,
+	}
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.itemsChanged(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		return s.todoItemRepository.Save(ctx, operations, todoItem)
+	})
+	if err != nil {
+		return &entities.Todo{}, &entities.TodoItem{}, err
+	}
+
+	return todo, todoItem, nil
+}
+
+func (s *todoItemController) ToggleTodoItem(ctx context.Context, dB db.DB, todoID, todoItemID int64) (*entities.Todo, *entities.TodoItem, error) {
+
+	todo, todoItem, err := s.todoItemForUser(ctx, dB, todoID, todoItemID)
+	if err != nil {
+		return &entities.Todo{}, &entities.TodoItem{}, err
+	}
+
+	todoItem.Completed = !todoItem.Completed
+	todoItem.CompletedAt = nil
+
+	if todoItem.Completed {
+		timeNow := time.Now()
+		todoItem.CompletedAt = &timeNow
+	}
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.itemsChanged(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		return s.todoItemRepository.Save(ctx, operations, todoItem)
+	})
+	if err != nil {
+		return &entities.Todo{}, &entities.TodoItem{}, err
+	}
+
+	return todo, todoItem, nil
+}
+
+func (s *todoItemController) ReorderTodoItems(ctx context.Context, dB db.DB, todoID int64, form *forms.ReorderTodoItemsForm) (*entities.Todo, []*entities.TodoItem, error) {
+
+	todo, err := s.todoForUser(ctx, dB, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, []*entities.TodoItem{}, err
+	}
+
+	var todoItems []*entities.TodoItem
+
+	// items written while the todo is locked wait, so the check below sees
+	// the same items the reorder changes
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.todoRepository.LockTodo(ctx, operations, todo.ID)
+		if err != nil {
+			return err
+		}
+
+		todoItems, err = s.todoItemRepository.TodoItems(ctx, operations, todo.ID)
+		if err != nil {
+			return err
+		}
+
+		currentIDs := make([]int64, 0, len(todoItems))
+		for _, todoItem := range todoItems {
+			currentIDs = append(currentIDs, todoItem.ID)
+		}
+
+		requestedIDs := slices.Clone(form.ItemIDs)
+
+		slices.Sort(currentIDs)
+		slices.Sort(requestedIDs)
+
+		if !slices.Equal(currentIDs, requestedIDs) {
+			return fmt.Errorf("item_ids must list every item of the todo exactly once")
+		}
+
+		err = s.todoItemRepository.ReorderTodoItems(ctx, operations, todo.ID, form.ItemIDs)
+		if err != nil {
+			return err
+		}
+
+		todoItems, err = s.todoItemRepository.TodoItems(ctx, operations, todo.ID)
+
+		return err
+	})
+	if err != nil {
+		return &entities.Todo{}, []*entities.TodoItem{}, err
+	}
+
+	return todo, todoItems, nil
+}
+
+func (s *todoItemController) DeleteTodoItem(ctx context.Context, dB db.DB, todoID, todoItemID int64) (*entities.Todo, error) {
+
+	todo, todoItem, err := s.todoItemForUser(ctx, dB, todoID, todoItemID)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.itemsChanged(ctx, operations, todo)
+		if err != nil {
+			return err
+		}
+
+		return s.todoItemRepository.DeleteTodoItem(ctx, operations, todoItem.ID)
+	})
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	return todo, nil
+}
+
+func (s *todoItemController) todoForUser(ctx context.Context, dB db.DB, todoID int64, access Access) (*entities.Todo, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	todo, err := s.todoRepository.TodoByID(ctx, dB, todoID)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	err = s.accessController.AuthorizeTodo(ctx, dB, user, todo, access)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	// the checklist is part of the todo, so writing an item needs the same
+	// If-Match as writing the todo
+	if access != AccessRead {
+		err = checkIfMatch(ctx, todo)
+		if err != nil {
+			return &entities.Todo{}, err
+		}
+	}
+
+	return todo, nil
+}
+
+func (s *todoItemController) todoItemForUser(ctx context.Context, dB db.DB, todoID, todoItemID int64) (*entities.Todo, *entities.TodoItem, error) {
+
+	todo, err := s.todoForUser(ctx, dB, todoID, AccessWrite)
+	if err != nil {
+		return &entities.Todo{}, &entities.TodoItem{}, err
+	}
+
+	todoItem, err := s.todoItemRepository.TodoItemByID(ctx, dB, todoItemID)
+	if err != nil {
+		return &entities.Todo{}, &entities.TodoItem{}, err
+	}
+
+	if todoItem.TodoID != todo.ID {
+		return &entities.Todo{}, &entities.TodoItem{}, apperror.NewDatabaseError(sql.ErrNoRows)
+	}
+
+	return todo, todoItem, nil
+}
+
+// itemsChanged moves the parent todo to a new version, since its item counts
+// are part of it, and drops it and the listings that show it from the cache
+// once operations commits. Call it before writing the items: the update
+// locks the todo against a concurrent reorder.
+func (s *todoItemController) itemsChanged(ctx context.Context, operations db.SQLOperations, todo *entities.Todo) error {
+
+	err := s.todoRepository.BumpVersion(ctx, operations, todo)
+	if err != nil {
+		return err
+	}
+
+	db.AfterCommit(operations, func() {
+
+		err := s.cacheController.RemoveFromCache(fmt.Sprintf(todoKeyPrefix, todo.ID))
+		logCacheError("del", err)
+
+		err = invalidateTodoLists(s.cacheController, todoListScopes(todo))
+		logCacheError("invalidate", err)
+	})
+
+	return nil
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/mocks"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTodoItemController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestTodoItemController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		redisManager := mocks.NewMockRedisProvider()
+
+		todoController := NewTestTodoController(redisManager)
+		todoItemController := NewTestTodoItemController(redisManager)
+
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
+		todo, err := todoController.CreateTodo(ctx, dB, &forms.CreateTodoForm{Title: "test", Description: "test"})
+		So(err, ShouldBeNil)
+
+		Convey("can create an item and see it in the todo progress", func() {
+
+			_, todoItem, err := todoItemController.CreateTodoItem(ctx, dB, todo.ID, &forms.CreateTodoItemForm{Title: "milk"})
+			So(err, ShouldBeNil)
+
+			So(todoItem.ID, ShouldNotBeZeroValue)
+			So(todoItem.Position, ShouldEqual, 1)
+
+			foundTodo, err := todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.ItemsTotal, ShouldEqual, 1)
+			So(foundTodo.Progress, ShouldEqual, "0/1 done")
+		})
+
+		Convey("can toggle an item", func() {
+
+			_, todoItem, err := todoItemController.CreateTodoItem(ctx, dB, todo.ID, &forms.CreateTodoItemForm{Title: "milk"})
+			So(err, ShouldBeNil)
+
+			_, toggledItem, err := todoItemController.ToggleTodoItem(ctx, dB, todo.ID, todoItem.ID)
+			So(err, ShouldBeNil)
+
+			So(toggledItem.Completed, ShouldBeTrue)
+			So(toggledItem.CompletedAt, ShouldNotBeNil)
+
+			_, toggledItem, err = todoItemController.ToggleTodoItem(ctx, dB, todo.ID, todoItem.ID)
+			So(err, ShouldBeNil)
+
+			So(toggledItem.Completed, ShouldBeFalse)
+			So(toggledItem.CompletedAt, ShouldBeNil)
+		})
+
+		Convey("cannot reorder with a partial list of items", func() {
+
+			_, first, err := todoItemController.CreateTodoItem(ctx, dB, todo.ID, &forms.CreateTodoItemForm{Title: "milk"})
+			So(err, ShouldBeNil)
+
+			_, _, err = todoItemController.CreateTodoItem(ctx, dB, todo.ID, &forms.CreateTodoItemForm{Title: "eggs"})
+			So(err, ShouldBeNil)
+
+			_, _, err = todoItemController.ReorderTodoItems(ctx, dB, todo.ID, &forms.ReorderTodoItemsForm{ItemIDs: []int64{first.ID}})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("cannot touch items of another user's todo", func() {
+
+			otherUser, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			otherTodo, err := repository.CreateTodo(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			todoItem, err := repository.CreateTodoItem(ctx, dB, otherTodo)
+			So(err, ShouldBeNil)
+
+			_, err = todoItemController.DeleteTodoItem(ctx, dB, otherTodo.ID, todoItem.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "sql: no rows in result set")
+		})
+
+		Convey("refuses to complete a todo with open items by default", func() {
+
+			_, _, err := todoItemController.CreateTodoItem(ctx, dB, todo.ID, &forms.CreateTodoItemForm{Title: "milk"})
+			So(err, ShouldBeNil)
+
+			_, err = todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "todo has 1 open items")
+		})
+
+		Convey("can cascade completion to open items", func() {
+
+			_, todoItem, err := todoItemController.CreateTodoItem(ctx, dB, todo.ID, &forms.CreateTodoItemForm{Title: "milk"})
+			So(err, ShouldBeNil)
+
+			completedTodo, err := todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{OpenItems: forms.OpenItemsCascade})
+			So(err, ShouldBeNil)
+
+			So(completedTodo.Completed, ShouldBeTrue)
+			So(completedTodo.Progress, ShouldEqual, "1/1 done")
+
+			todoItems, err := todoItemController.TodoItems(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(todoItems[0].ID, ShouldEqual, todoItem.ID)
+			So(todoItems[0].Completed, ShouldBeTrue)
+		})
+
+		Convey("moves the todo to a new version when its checklist changes", func() {
+
+			parent, todoItem, err := todoItemController.CreateTodoItem(ctx, dB, todo.ID, &forms.CreateTodoItemForm{Title: "milk"})
+			So(err, ShouldBeNil)
+
+			created, err := todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(created.Version, ShouldBeGreaterThan, todo.Version)
+			So(parent.ETag(), ShouldEqual, created.ETag())
+
+			_, _, err = todoItemController.ToggleTodoItem(ctx, dB, todo.ID, todoItem.ID)
+			So(err, ShouldBeNil)
+
+			toggled, err := todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(toggled.Version, ShouldBeGreaterThan, created.Version)
+
+			_, err = todoItemController.DeleteTodoItem(ctx, dB, todo.ID, todoItem.ID)
+			So(err, ShouldBeNil)
+
+			deleted, err := todoController.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(deleted.Version, ShouldBeGreaterThan, toggled.Version)
+
+			title := "stale"
+
+			_, err = todoController.UpdateTodo(contexthelper.WithIfMatch(ctx, toggled.ETag()), dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("refuses checklist writes made against a stale version of the todo", func() {
+
+			parent, todoItem, err := todoItemController.CreateTodoItem(contexthelper.WithIfMatch(ctx, todo.ETag()), dB, todo.ID, &forms.CreateTodoItemForm{Title: "milk"})
+			So(err, ShouldBeNil)
+
+			staleCtx := contexthelper.WithIfMatch(ctx, todo.ETag())
+
+			_, _, err = todoItemController.ToggleTodoItem(staleCtx, dB, todo.ID, todoItem.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "todo has been changed since it was fetched")
+
+			_, err = todoItemController.DeleteTodoItem(staleCtx, dB, todo.ID, todoItem.ID)
+			So(err, ShouldNotBeNil)
+
+			_, _, err = todoItemController.ToggleTodoItem(contexthelper.WithIfMatch(ctx, parent.ETag()), dB, todo.ID, todoItem.ID)
+			So(err, ShouldBeNil)
+		})
+	}))
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/repository"
+)
+
+const (
+	defaultTrashRetention = 30 * 24 * time.Hour
+	trashPurgeBatchSize   = 100
+)
+
+type (
+	// TrashController permanently removes todos that have been in the trash
+	// for longer than the retention window.
+	TrashController interface {
+		PurgeExpired(ctx context.Context, dB db.DB) (int, error)
+		Run(ctx context.Context, dB db.DB, interval time.Duration)
+	}
+
+	TrashConfig struct {
+		Retention time.Duration
+	}
+
+	trashController struct {
+		attachmentRepository repository.AttachmentRepository
+		blobStore            providers.BlobStore
+		retention            time.Duration
+		todoRepository       repository.TodoRepository
+	}
+)
+
+func NewTrashController(
+	config *TrashConfig,
+	attachmentRepository repository.AttachmentRepository,
+	blobStore providers.BlobStore,
+	todoRepository repository.TodoRepository,
+) TrashController {
+
+	retention := defaultTrashRetention
+
+	if value := os.Getenv("TRASH_RETENTION"); value != "" {
+
+		duration, err := time.ParseDuration(value)
+		if err != nil || duration <= 0 {
+			log.Printf("invalid TRASH_RETENTION %q, using %v", value, retention)
+		} else {
+			retention = duration
+		}
+	}
+
+	if config != nil && config.Retention > 0 {
+		retention = config.Retention
+	}
+
+	return &trashController{
+		attachmentRepository: attachmentRepository,
+		blobStore:            blobStore,
+		retention:            retention,
+		todoRepository:       todoRepository,
+	}
+}
+
+func NewTestTrashController(
+	blobStore providers.BlobStore,
+	retention time.Duration,
+) *trashController {
+	return &trashController{
+		attachmentRepository: repository.NewAttachmentRepository(),
+		blobStore:            blobStore,
+		retention:            retention,
+		todoRepository:       repository.NewTodoRepository(),
+	}
+}
+
+// PurgeExpired removes expired todos in batches so a large backlog never
+// holds one long transaction, and returns how many were removed.
+func (s *trashController) PurgeExpired(ctx context.Context, dB db.DB) (int, error) {
+
+	deletedBefore := time.Now().Add(-s.retention)
+
+	purged := 0
+
+	for {
+
+		var purgedIDs []int64
+		var storageKeys []string
+		var todoIDs []int64
+
+		err := dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+			var err error
+
+			todoIDs, err = s.todoRepository.PurgeableTodoIDs(ctx, operations, deletedBefore, trashPurgeBatchSize)
+			if err != nil || len(todoIDs) == 0 {
+				return err
+			}
+
+			storageKeysByTodo, err := s.attachmentRepository.StorageKeysForTodos(ctx, operations, todoIDs)
+			if err != nil {
+				return err
+			}
+
+			// a todo restored since it was picked is not purged, and its
+			// blobs stay with it
+			purgedIDs, err = s.todoRepository.PurgeTodos(ctx, operations, todoIDs)
+			if err != nil {
+				return err
+			}
+
+			for _, todoID := range purgedIDs {
+				storageKeys = append(storageKeys, storageKeysByTodo[todoID]...)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		// the attachment rows go with the todos, their blobs only once that
+		// is committed
+		deleteBlobs(s.blobStore, storageKeys)
+
+		purged += len(purgedIDs)
+
+		if len(todoIDs) < trashPurgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// Run purges the trash straight away and then every interval until ctx is
+// done.
+func (s *trashController) Run(ctx context.Context, dB db.DB, interval time.Duration) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+
+		purged, err := s.PurgeExpired(ctx, dB)
+		if err != nil {
+			log.Printf("purge trash error: %v", err)
+		} else if purged > 0 {
+			log.Printf("purged %d todos from the trash", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
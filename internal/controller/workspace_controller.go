@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+)
+
+const (
+	invitationTokenSize = 32
+	invitationTTL       = 7 * 24 * time.Hour
+)
+
+type (
+	WorkspaceController interface {
+		AcceptInvitation(ctx context.Context, dB db.DB, form *forms.AcceptInvitationForm) (*entities.WorkspaceMember, error)
+		CreateInvitation(ctx context.Context, dB db.DB, workspaceID int64, form *forms.CreateInvitationForm) (*entities.WorkspaceInvitation, error)
+		CreateWorkspace(ctx context.Context, dB db.DB, form *forms.CreateWorkspaceForm) (*entities.Workspace, error)
+		Members(ctx context.Context, dB db.DB, workspaceID int64) ([]*entities.WorkspaceMember, error)
+		RemoveMember(ctx context.Context, dB db.DB, workspaceID, userID int64) error
+		UpdateMember(ctx context.Context, dB db.DB, workspaceID, userID int64, form *forms.UpdateMemberForm) (*entities.WorkspaceMember, error)
+		WorkspaceByID(ctx context.Context, dB db.DB, workspaceID int64) (*entities.Workspace, error)
+		Workspaces(ctx context.Context, dB db.DB) ([]*entities.Workspace, error)
+	}
+
+	workspaceController struct {
+		accessController              AccessController
+		workspaceInvitationRepository repository.WorkspaceInvitationRepository
+		workspaceRepository           repository.WorkspaceRepository
+	}
+)
+
+func NewWorkspaceController(
+	accessController AccessController,
+	workspaceInvitationRepository repository.WorkspaceInvitationRepository,
+	workspaceRepository repository.WorkspaceRepository,
+) WorkspaceController {
+	return &workspaceController{
+		accessController:              accessController,
+		workspaceInvitationRepository: workspaceInvitationRepository,
+		workspaceRepository:           workspaceRepository,
+	}
+}
+
+func NewTestWorkspaceController() *workspaceController {
+	return &workspaceController{
+		accessController:              NewTestAccessController(),
+		workspaceInvitationRepository: repository.NewWorkspaceInvitationRepository(),
+		workspaceRepository:           repository.NewWorkspaceRepository(),
+	}
+}
+
+func (s *workspaceController) CreateWorkspace(ctx context.Context, dB db.DB, form *forms.CreateWorkspaceForm) (*entities.Workspace, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Workspace{}, err
+	}
+
+	err = utils.ValidateSingleName(form.Name)
+	if err != nil {
+		return &entities.Workspace{}, err
+	}
+
+	workspace := &entities.Workspace{
+		Name: strings.TrimSpace(form.Name),
+		Role: entities.WorkspaceRoleOwner,
+	}
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.workspaceRepository.Save(ctx, operations, workspace)
+		if err != nil {
+			return err
+		}
+
+		return s.workspaceRepository.SaveMember(ctx, operations, &entities.WorkspaceMember{
+			WorkspaceID: workspace.ID,
+			UserID:      user.ID,
+			Role:        entities.WorkspaceRoleOwner,
+		})
+	})
+	if err != nil {
+		return &entities.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+func (s *workspaceController) Workspaces(ctx context.Context, dB db.DB) ([]*entities.Workspace, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return []*entities.Workspace{}, err
+	}
+
+	return s.workspaceRepository.WorkspacesByUser(ctx, dB, user.ID)
+}
+
+func (s *workspaceController) WorkspaceByID(ctx context.Context, dB db.DB, workspaceID int64) (*entities.Workspace, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.Workspace{}, err
+	}
+
+	member, err := s.accessController.AuthorizeWorkspace(ctx, dB, user, workspaceID, AccessRead)
+	if err != nil {
+		return &entities.Workspace{}, err
+	}
+
+	workspace, err := s.workspaceRepository.WorkspaceByID(ctx, dB, workspaceID)
+	if err != nil {
+		return &entities.Workspace{}, err
+	}
+
+	workspace.Role = member.Role
+
+	return workspace, nil
+}
+
+func (s *workspaceController) Members(ctx context.Context, dB db.DB, workspaceID int64) ([]*entities.WorkspaceMember, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return []*entities.WorkspaceMember{}, err
+	}
+
+	_, err = s.accessController.AuthorizeWorkspace(ctx, dB, user, workspaceID, AccessRead)
+	if err != nil {
+		return []*entities.WorkspaceMember{}, err
+	}
+
+	return s.workspaceRepository.Members(ctx, dB, workspaceID)
+}
+
+func (s *workspaceController) UpdateMember(ctx context.Context, dB db.DB, workspaceID, userID int64, form *forms.UpdateMemberForm) (*entities.WorkspaceMember, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	_, err = s.accessController.AuthorizeWorkspace(ctx, dB, user, workspaceID, AccessManage)
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	if !form.Role.Valid() {
+		return &entities.WorkspaceMember{}, invalidRoleError(form.Role)
+	}
+
+	member, err := s.workspaceRepository.MemberByUser(ctx, dB, workspaceID, userID)
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	if member.Role == entities.WorkspaceRoleOwner && form.Role != entities.WorkspaceRoleOwner {
+
+		err = s.ensureAnotherOwner(ctx, dB, workspaceID)
+		if err != nil {
+			return &entities.WorkspaceMember{}, err
+		}
+	}
+
+	member.Role = form.Role
+
+	err = s.workspaceRepository.SaveMember(ctx, dB, member)
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	return member, nil
+}
+
+// RemoveMember lets owners remove anyone and every member leave on their own.
+func (s *workspaceController) RemoveMember(ctx context.Context, dB db.DB, workspaceID, userID int64) error {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	access := AccessManage
+	if userID == user.ID {
+		access = AccessRead
+	}
+
+	_, err = s.accessController.AuthorizeWorkspace(ctx, dB, user, workspaceID, access)
+	if err != nil {
+		return err
+	}
+
+	member, err := s.workspaceRepository.MemberByUser(ctx, dB, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == entities.WorkspaceRoleOwner {
+
+		err = s.ensureAnotherOwner(ctx, dB, workspaceID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.workspaceRepository.DeleteMember(ctx, dB, workspaceID, userID)
+}
+
+// CreateInvitation returns the invitation token once; only its hash is stored.
+func (s *workspaceController) CreateInvitation(ctx context.Context, dB db.DB, workspaceID int64, form *forms.CreateInvitationForm) (*entities.WorkspaceInvitation, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.WorkspaceInvitation{}, err
+	}
+
+	_, err = s.accessController.AuthorizeWorkspace(ctx, dB, user, workspaceID, AccessManage)
+	if err != nil {
+		return &entities.WorkspaceInvitation{}, err
+	}
+
+	err = utils.ValidateEmail(form.Email)
+	if err != nil {
+		return &entities.WorkspaceInvitation{}, err
+	}
+
+	role := form.Role
+	if role == "" {
+		role = entities.WorkspaceRoleViewer
+	}
+
+	if !role.Valid() {
+		return &entities.WorkspaceInvitation{}, invalidRoleError(role)
+	}
+
+	token, err := utils.GenerateToken(invitationTokenSize)
+	if err != nil {
+		return &entities.WorkspaceInvitation{}, err
+	}
+
+	invitation := &entities.WorkspaceInvitation{
+		WorkspaceID: workspaceID,
+		InvitedBy:   user.ID,
+		Email:       form.Email,
+		Role:        role,
+		TokenHash:   utils.HashToken(token),
+		ExpiresAt:   time.Now().Add(invitationTTL),
+	}
+
+	err = s.workspaceInvitationRepository.Save(ctx, dB, invitation)
+	if err != nil {
+		return &entities.WorkspaceInvitation{}, err
+	}
+
+	invitation.Token = token
+
+	return invitation, nil
+}
+
+func (s *workspaceController) AcceptInvitation(ctx context.Context, dB db.DB, form *forms.AcceptInvitationForm) (*entities.WorkspaceMember, error) {
+
+	user, err := authenticatedUser(ctx)
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	invitation, err := s.workspaceInvitationRepository.InvitationByHash(ctx, dB, utils.HashToken(form.Token))
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	timeNow := time.Now()
+
+	if !invitation.Pending(timeNow) {
+		return &entities.WorkspaceMember{}, fmt.Errorf("invitation has expired or has already been accepted")
+	}
+
+	if !strings.EqualFold(invitation.Email, user.Email) {
+		return &entities.WorkspaceMember{}, forbidden("invitation was sent to a different email address")
+	}
+
+	_, err = s.workspaceRepository.MemberByUser(ctx, dB, invitation.WorkspaceID, user.ID)
+	if err == nil {
+		return &entities.WorkspaceMember{}, apperror.Wrap(errors.New("already a member of this workspace")).SetHttpStatusCode(http.StatusConflict)
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	member := &entities.WorkspaceMember{
+		WorkspaceID: invitation.WorkspaceID,
+		UserID:      user.ID,
+		Email:       user.Email,
+		Role:        invitation.Role,
+	}
+
+	err = dB.InTransaction(ctx, func(operations db.SQLOperations) error {
+
+		err := s.workspaceRepository.SaveMember(ctx, operations, member)
+		if err != nil {
+			return err
+		}
+
+		invitation.AcceptedAt = &timeNow
+
+		return s.workspaceInvitationRepository.Save(ctx, operations, invitation)
+	})
+	if err != nil {
+		return &entities.WorkspaceMember{}, err
+	}
+
+	return member, nil
+}
+
+func (s *workspaceController) ensureAnotherOwner(ctx context.Context, dB db.DB, workspaceID int64) error {
+
+	owners, err := s.workspaceRepository.NumberOfOwners(ctx, dB, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return fmt.Errorf("a workspace must keep at least one owner")
+	}
+
+	return nil
+}
+
+func invalidRoleError(role entities.WorkspaceRole) error {
+	return fmt.Errorf("invalid role %v, allowed roles are %v, %v and %v", role, entities.WorkspaceRoleOwner, entities.WorkspaceRoleEditor, entities.WorkspaceRoleViewer)
+}
@@ -0,0 +1,192 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/mocks"
+	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWorkspaceController(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestWorkspaceController", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		redisManager := mocks.NewMockRedisProvider()
+
+		todoController := NewTestTodoController(redisManager)
+		workspaceController := NewTestWorkspaceController()
+
+		owner, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		member, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ownerCtx := contexthelper.WithUser(ctx, owner)
+		memberCtx := contexthelper.WithUser(ctx, member)
+
+		workspace, err := workspaceController.CreateWorkspace(ownerCtx, dB, &forms.CreateWorkspaceForm{Name: "team"})
+		So(err, ShouldBeNil)
+
+		So(workspace.Role, ShouldEqual, entities.WorkspaceRoleOwner)
+
+		todo, err := todoController.CreateTodo(ownerCtx, dB, &forms.CreateTodoForm{
+			Title:       "test",
+			Description: "test",
+			WorkspaceID: &workspace.ID,
+		})
+		So(err, ShouldBeNil)
+
+		join := func(role entities.WorkspaceRole) {
+
+			invitation, err := workspaceController.CreateInvitation(ownerCtx, dB, workspace.ID, &forms.CreateInvitationForm{
+				Email: member.Email,
+				Role:  role,
+			})
+			So(err, ShouldBeNil)
+
+			So(invitation.Token, ShouldNotBeEmpty)
+
+			joined, err := workspaceController.AcceptInvitation(memberCtx, dB, &forms.AcceptInvitationForm{Token: invitation.Token})
+			So(err, ShouldBeNil)
+
+			So(joined.Role, ShouldEqual, role)
+		}
+
+		Convey("hides workspace todos from non members", func() {
+
+			_, err := todoController.TodoByID(memberCtx, dB, todo.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "sql: no rows in result set")
+		})
+
+		Convey("lets viewers read but not change workspace todos", func() {
+
+			join(entities.WorkspaceRoleViewer)
+
+			foundTodo, err := todoController.TodoByID(memberCtx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.ID, ShouldEqual, todo.ID)
+
+			todos, err := todoController.Todos(memberCtx, dB, &forms.Filter{WorkspaceID: workspace.ID})
+			So(err, ShouldBeNil)
+
+			So(len(todos.Todos), ShouldEqual, 1)
+
+			title := "changed"
+
+			_, err = todoController.UpdateTodo(memberCtx, dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusForbidden)
+
+			err = todoController.DeleteTodo(memberCtx, dB, todo.ID)
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("lets editors change workspace todos", func() {
+
+			join(entities.WorkspaceRoleEditor)
+
+			title := "changed"
+
+			updatedTodo, err := todoController.UpdateTodo(memberCtx, dB, todo.ID, &forms.UpdateTodoForm{Title: &title})
+			So(err, ShouldBeNil)
+
+			So(updatedTodo.Title, ShouldEqual, title)
+		})
+
+		Convey("does not accept an invitation sent to someone else", func() {
+
+			invitation, err := workspaceController.CreateInvitation(ownerCtx, dB, workspace.ID, &forms.CreateInvitationForm{
+				Email: "someone@example.com",
+			})
+			So(err, ShouldBeNil)
+
+			_, err = workspaceController.AcceptInvitation(memberCtx, dB, &forms.AcceptInvitationForm{Token: invitation.Token})
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("only owners can invite members", func() {
+
+			join(entities.WorkspaceRoleEditor)
+
+			_, err := workspaceController.CreateInvitation(memberCtx, dB, workspace.ID, &forms.CreateInvitationForm{
+				Email: "someone@example.com",
+			})
+			So(err, ShouldNotBeNil)
+
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("cannot demote or remove the last owner", func() {
+
+			_, err := workspaceController.UpdateMember(ownerCtx, dB, workspace.ID, owner.ID, &forms.UpdateMemberForm{Role: entities.WorkspaceRoleEditor})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "a workspace must keep at least one owner")
+
+			err = workspaceController.RemoveMember(ownerCtx, dB, workspace.ID, owner.ID)
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "a workspace must keep at least one owner")
+		})
+
+		Convey("assigns workspace todos to members only", func() {
+
+			outsider, err := repository.CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.AssignTodo(ownerCtx, dB, todo.ID, &forms.AssignTodoForm{AssigneeID: outsider.ID})
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "assignee is not a member of this workspace")
+
+			join(entities.WorkspaceRoleEditor)
+
+			assignedTodo, err := todoController.AssignTodo(ownerCtx, dB, todo.ID, &forms.AssignTodoForm{AssigneeID: member.ID})
+			So(err, ShouldBeNil)
+
+			So(*assignedTodo.AssigneeID, ShouldEqual, member.ID)
+
+			todos, err := todoController.TodosAssignedToMe(memberCtx, dB, &forms.Filter{})
+			So(err, ShouldBeNil)
+
+			So(len(todos.Todos), ShouldEqual, 1)
+			So(todos.Todos[0].ID, ShouldEqual, todo.ID)
+
+			unassignedTodo, err := todoController.UnassignTodo(ownerCtx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(unassignedTodo.AssigneeID, ShouldBeNil)
+
+			activities, err := todoController.TodoActivities(memberCtx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(len(activities), ShouldEqual, 2)
+			So(activities[0].Action, ShouldEqual, entities.TodoActivityAssigned)
+			So(activities[1].Action, ShouldEqual, entities.TodoActivityUnassigned)
+			So(*activities[1].AssigneeID, ShouldEqual, member.ID)
+		})
+	}))
+}
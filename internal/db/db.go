@@ -19,6 +19,7 @@ type DB interface {
 	SQLOperations
 	Begin() (*sql.Tx, error)
 	Close() error
+	InTransaction(ctx context.Context, f func(operations SQLOperations) error) error
 	Ping() error
 	Valid() bool
 }
@@ -27,11 +28,21 @@ type RowScanner interface {
 	Scan(dest ...any) error
 }
 
+// afterCommitter is implemented by the operations InTransaction hands out.
+type afterCommitter interface {
+	afterCommit(f func())
+}
+
 type AppDB struct {
 	*sql.DB
 	valid bool
 }
 
+type transaction struct {
+	*sql.Tx
+	hooks []func()
+}
+
 func InitDB() DB {
 	return initDBWithURL(os.Getenv("DATABASE_URL"))
 }
@@ -62,3 +73,55 @@ func initDBWithURL(databaseURL string) DB {
 func (db *AppDB) Valid() bool {
 	return db.valid
 }
+
+// InTransaction runs f in a transaction that is committed when f returns nil
+// and rolled back otherwise.
+func (db *AppDB) InTransaction(ctx context.Context, f func(operations SQLOperations) error) error {
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	// a no-op once the transaction has been committed
+	defer tx.Rollback()
+
+	operations := &transaction{Tx: tx}
+
+	err = f(operations)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	runHooks(operations.hooks)
+
+	return nil
+}
+
+// AfterCommit runs f once the transaction behind operations has committed
+// and drops it if the transaction rolls back. Work done outside a
+// transaction is committed as it happens, so f runs straight away.
+func AfterCommit(operations SQLOperations, f func()) {
+
+	if tx, ok := operations.(afterCommitter); ok {
+		tx.afterCommit(f)
+		return
+	}
+
+	f()
+}
+
+func (t *transaction) afterCommit(f func()) {
+	t.hooks = append(t.hooks, f)
+}
+
+func runHooks(hooks []func()) {
+	for _, hook := range hooks {
+		hook()
+	}
+}
@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE users(
+    id                  BIGSERIAL       PRIMARY KEY,
+    email               VARCHAR(255)    NOT NULL        UNIQUE,
+    password_hash       TEXT            NOT NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+ALTER TABLE todos ADD COLUMN owner_id BIGINT NULL REFERENCES users(id) ON DELETE CASCADE;
+
+-- todos created before users existed go to a legacy user that cannot log in
+INSERT INTO users (email, password_hash) SELECT 'legacy@todo.invalid', '!' WHERE EXISTS (SELECT 1 FROM todos);
+
+UPDATE todos SET owner_id = (SELECT id FROM users WHERE email = 'legacy@todo.invalid') WHERE owner_id IS NULL;
+
+ALTER TABLE todos ALTER COLUMN owner_id SET NOT NULL;
+
+CREATE INDEX todos_owner_id_idx ON todos(owner_id);
+-- +goose Down
+DROP INDEX IF EXISTS todos_owner_id_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS owner_id;
+
+DROP TABLE IF EXISTS users;
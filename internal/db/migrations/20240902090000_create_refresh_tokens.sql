@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE refresh_tokens(
+    id                  BIGSERIAL       PRIMARY KEY,
+    user_id             BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    token_hash          VARCHAR(64)     NOT NULL        UNIQUE,
+    expires_at          TIMESTAMPTZ     NOT NULL,
+    revoked_at          TIMESTAMPTZ     NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX refresh_tokens_user_id_idx ON refresh_tokens(user_id);
+-- +goose Down
+DROP INDEX IF EXISTS refresh_tokens_user_id_idx;
+
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE api_keys(
+    id                  BIGSERIAL       PRIMARY KEY,
+    user_id             BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    name                VARCHAR(50)     NOT NULL,
+    prefix              VARCHAR(16)     NOT NULL,
+    key_hash            VARCHAR(64)     NOT NULL        UNIQUE,
+    scope               VARCHAR(20)     NOT NULL        CHECK (scope IN ('read', 'read_write')),
+    last_used_at        TIMESTAMPTZ     NULL,
+    revoked_at          TIMESTAMPTZ     NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX api_keys_user_id_idx ON api_keys(user_id);
+-- +goose Down
+DROP INDEX IF EXISTS api_keys_user_id_idx;
+
+DROP TABLE IF EXISTS api_keys;
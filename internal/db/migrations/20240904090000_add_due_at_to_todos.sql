@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE todos ADD COLUMN due_at TIMESTAMPTZ NULL;
+
+CREATE INDEX todos_owner_id_due_at_idx ON todos(owner_id, due_at);
+-- +goose Down
+DROP INDEX IF EXISTS todos_owner_id_due_at_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS due_at;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE todos ADD COLUMN priority SMALLINT NOT NULL DEFAULT 0 CHECK (priority BETWEEN 0 AND 4);
+
+CREATE INDEX todos_owner_id_priority_idx ON todos(owner_id, priority);
+-- +goose Down
+DROP INDEX IF EXISTS todos_owner_id_priority_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS priority;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE tags(
+    id                  BIGSERIAL       PRIMARY KEY,
+    owner_id            BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    name                VARCHAR(50)     NOT NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    UNIQUE (owner_id, name)
+);
+
+CREATE TABLE todo_tags(
+    todo_id             BIGINT          NOT NULL        REFERENCES todos(id) ON DELETE CASCADE,
+    tag_id              BIGINT          NOT NULL        REFERENCES tags(id) ON DELETE CASCADE,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    PRIMARY KEY (todo_id, tag_id)
+);
+
+CREATE INDEX todo_tags_tag_id_idx ON todo_tags(tag_id);
+-- +goose Down
+DROP INDEX IF EXISTS todo_tags_tag_id_idx;
+
+DROP TABLE IF EXISTS todo_tags;
+
+DROP TABLE IF EXISTS tags;
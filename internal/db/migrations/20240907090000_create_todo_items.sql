@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE todo_items(
+    id                  BIGSERIAL       PRIMARY KEY,
+    todo_id             BIGINT          NOT NULL        REFERENCES todos(id) ON DELETE CASCADE,
+    title               VARCHAR(255)    NOT NULL,
+    completed           BOOLEAN         NOT NULL        DEFAULT FALSE,
+    completed_at        TIMESTAMPTZ     NULL,
+    position            INTEGER         NOT NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX todo_items_todo_id_position_idx ON todo_items(todo_id, position);
+-- +goose Down
+DROP INDEX IF EXISTS todo_items_todo_id_position_idx;
+
+DROP TABLE IF EXISTS todo_items;
@@ -0,0 +1,14 @@
+-- +goose Up
+ALTER TABLE todos ADD COLUMN recurrence_rule VARCHAR(255) NULL;
+ALTER TABLE todos ADD COLUMN series_id BIGINT NULL;
+ALTER TABLE todos ADD COLUMN series_start_at TIMESTAMPTZ NULL;
+ALTER TABLE todos ADD COLUMN occurrence INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX todos_series_id_idx ON todos(series_id);
+-- +goose Down
+DROP INDEX IF EXISTS todos_series_id_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS occurrence;
+ALTER TABLE todos DROP COLUMN IF EXISTS series_start_at;
+ALTER TABLE todos DROP COLUMN IF EXISTS series_id;
+ALTER TABLE todos DROP COLUMN IF EXISTS recurrence_rule;
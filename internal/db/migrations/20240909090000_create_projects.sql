@@ -0,0 +1,23 @@
+-- +goose Up
+CREATE TABLE projects(
+    id                  BIGSERIAL       PRIMARY KEY,
+    owner_id            BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    name                VARCHAR(50)     NOT NULL,
+    archived_at         TIMESTAMPTZ     NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX projects_owner_id_idx ON projects(owner_id);
+
+ALTER TABLE todos ADD COLUMN project_id BIGINT NULL REFERENCES projects(id) ON DELETE SET NULL;
+
+CREATE INDEX todos_project_id_idx ON todos(project_id);
+-- +goose Down
+DROP INDEX IF EXISTS todos_project_id_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS project_id;
+
+DROP INDEX IF EXISTS projects_owner_id_idx;
+
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,51 @@
+-- +goose Up
+CREATE TABLE workspaces(
+    id                  BIGSERIAL       PRIMARY KEY,
+    name                VARCHAR(50)     NOT NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE TABLE workspace_members(
+    workspace_id        BIGINT          NOT NULL        REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id             BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    role                VARCHAR(20)     NOT NULL        CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX workspace_members_user_id_idx ON workspace_members(user_id);
+
+CREATE TABLE workspace_invitations(
+    id                  BIGSERIAL       PRIMARY KEY,
+    workspace_id        BIGINT          NOT NULL        REFERENCES workspaces(id) ON DELETE CASCADE,
+    invited_by          BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    email               VARCHAR(255)    NOT NULL,
+    role                VARCHAR(20)     NOT NULL        CHECK (role IN ('owner', 'editor', 'viewer')),
+    token_hash          VARCHAR(64)     NOT NULL        UNIQUE,
+    expires_at          TIMESTAMPTZ     NOT NULL,
+    accepted_at         TIMESTAMPTZ     NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX workspace_invitations_workspace_id_idx ON workspace_invitations(workspace_id);
+
+ALTER TABLE todos ADD COLUMN workspace_id BIGINT NULL REFERENCES workspaces(id) ON DELETE CASCADE;
+
+CREATE INDEX todos_workspace_id_idx ON todos(workspace_id);
+-- +goose Down
+DROP INDEX IF EXISTS todos_workspace_id_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS workspace_id;
+
+DROP INDEX IF EXISTS workspace_invitations_workspace_id_idx;
+
+DROP TABLE IF EXISTS workspace_invitations;
+
+DROP INDEX IF EXISTS workspace_members_user_id_idx;
+
+DROP TABLE IF EXISTS workspace_members;
+
+DROP TABLE IF EXISTS workspaces;
@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE todos ADD COLUMN assignee_id BIGINT NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX todos_assignee_id_idx ON todos(assignee_id);
+
+CREATE TABLE todo_activities(
+    id                  BIGSERIAL       PRIMARY KEY,
+    todo_id             BIGINT          NOT NULL        REFERENCES todos(id) ON DELETE CASCADE,
+    actor_id            BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    action              VARCHAR(50)     NOT NULL,
+    assignee_id         BIGINT          NULL            REFERENCES users(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX todo_activities_todo_id_idx ON todo_activities(todo_id);
+-- +goose Down
+DROP INDEX IF EXISTS todo_activities_todo_id_idx;
+
+DROP TABLE IF EXISTS todo_activities;
+
+DROP INDEX IF EXISTS todos_assignee_id_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS assignee_id;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE comments(
+    id                  BIGSERIAL       PRIMARY KEY,
+    todo_id             BIGINT          NOT NULL        REFERENCES todos(id) ON DELETE CASCADE,
+    author_id           BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    body                TEXT            NOT NULL,
+    edited_at           TIMESTAMPTZ     NULL,
+    deleted_at          TIMESTAMPTZ     NULL,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX comments_todo_id_created_at_idx ON comments(todo_id, created_at);
+-- +goose Down
+DROP INDEX IF EXISTS comments_todo_id_created_at_idx;
+
+DROP TABLE IF EXISTS comments;
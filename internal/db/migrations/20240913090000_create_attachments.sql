@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE attachments(
+    id                  BIGSERIAL       PRIMARY KEY,
+    todo_id             BIGINT          NOT NULL        REFERENCES todos(id) ON DELETE CASCADE,
+    uploaded_by         BIGINT          NOT NULL        REFERENCES users(id) ON DELETE CASCADE,
+    file_name           VARCHAR(255)    NOT NULL,
+    content_type        VARCHAR(100)    NOT NULL,
+    size                BIGINT          NOT NULL,
+    checksum            CHAR(64)        NOT NULL,
+    storage_key         VARCHAR(255)    NOT NULL        UNIQUE,
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp(),
+    updated_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX attachments_todo_id_idx ON attachments(todo_id);
+-- +goose Down
+DROP INDEX IF EXISTS attachments_todo_id_idx;
+
+DROP TABLE IF EXISTS attachments;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE todo_events(
+    id                  BIGSERIAL       PRIMARY KEY,
+    todo_id             BIGINT          NOT NULL,
+    actor_id            BIGINT          NULL            REFERENCES users(id) ON DELETE SET NULL,
+    action              VARCHAR(20)     NOT NULL,
+    request_id          VARCHAR(100)    NOT NULL        DEFAULT '',
+    user_agent          TEXT            NOT NULL        DEFAULT '',
+    changes             JSONB           NOT NULL        DEFAULT '{}',
+    created_at          TIMESTAMPTZ     NOT NULL        DEFAULT clock_timestamp()
+);
+
+CREATE INDEX todo_events_todo_id_idx ON todo_events(todo_id, created_at);
+-- +goose Down
+DROP INDEX IF EXISTS todo_events_todo_id_idx;
+
+DROP TABLE IF EXISTS todo_events;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE todos ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+
+CREATE INDEX todos_deleted_at_idx ON todos(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose Down
+DROP INDEX IF EXISTS todos_deleted_at_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS deleted_at;
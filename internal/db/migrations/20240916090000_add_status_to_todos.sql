@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE todos ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'in_progress', 'blocked', 'done', 'cancelled'));
+
+UPDATE todos SET status = 'done' WHERE completed;
+
+CREATE INDEX todos_status_idx ON todos(status);
+-- +goose Down
+DROP INDEX IF EXISTS todos_status_idx;
+
+ALTER TABLE todos DROP COLUMN IF EXISTS status;
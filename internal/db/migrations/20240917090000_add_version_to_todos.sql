@@ -0,0 +1,4 @@
+-- +goose Up
+ALTER TABLE todos ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+-- +goose Down
+ALTER TABLE todos DROP COLUMN IF EXISTS version;
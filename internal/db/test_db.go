@@ -1,10 +1,18 @@
 package db
 
-import "database/sql"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
 
 type TestDB struct {
 	*sql.Tx
-	valid bool
+	// hooks holds the after commit hooks of each open savepoint, innermost
+	// last
+	hooks      [][]func()
+	savepoints int
+	valid      bool
 }
 
 func (db *TestDB) Begin() (*sql.Tx, error) {
@@ -15,6 +23,61 @@ func (db *TestDB) Close() error {
 	return nil
 }
 
+// InTransaction uses a savepoint because tests already run inside a
+// transaction that is rolled back when the test finishes.
+func (db *TestDB) InTransaction(ctx context.Context, f func(operations SQLOperations) error) error {
+
+	db.savepoints++
+	savepoint := fmt.Sprintf("test_savepoint_%d", db.savepoints)
+
+	_, err := db.ExecContext(ctx, "SAVEPOINT "+savepoint)
+	if err != nil {
+		return err
+	}
+
+	db.hooks = append(db.hooks, nil)
+
+	err = f(db)
+
+	hooks := db.hooks[len(db.hooks)-1]
+	db.hooks = db.hooks[:len(db.hooks)-1]
+
+	if err != nil {
+
+		_, rollbackErr := db.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+savepoint)
+		if rollbackErr != nil {
+			return rollbackErr
+		}
+
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, "RELEASE SAVEPOINT "+savepoint)
+	if err != nil {
+		return err
+	}
+
+	// releasing an inner savepoint only hands its hooks to the outer one,
+	// the outermost release stands in for a commit
+	if len(db.hooks) > 0 {
+		db.hooks[len(db.hooks)-1] = append(db.hooks[len(db.hooks)-1], hooks...)
+	} else {
+		runHooks(hooks)
+	}
+
+	return nil
+}
+
+func (db *TestDB) afterCommit(f func()) {
+
+	if len(db.hooks) == 0 {
+		f()
+		return
+	}
+
+	db.hooks[len(db.hooks)-1] = append(db.hooks[len(db.hooks)-1], f)
+}
+
 func (db *TestDB) Ping() error {
 	return nil
 }
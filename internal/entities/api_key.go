@@ -0,0 +1,35 @@
+package entities
+
+import "time"
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeRead      APIKeyScope = "read"
+	APIKeyScopeReadWrite APIKeyScope = "read_write"
+)
+
+func (s APIKeyScope) CanWrite() bool {
+	return s == APIKeyScopeReadWrite
+}
+
+func (s APIKeyScope) Valid() bool {
+	return s == APIKeyScopeRead || s == APIKeyScopeReadWrite
+}
+
+type APIKey struct {
+	Identifier
+	UserID     int64       `json:"user_id"`
+	Name       string      `json:"name"`
+	Prefix     string      `json:"prefix"`
+	Key        string      `json:"key,omitempty"`
+	KeyHash    string      `json:"-"`
+	Scope      APIKeyScope `json:"scope"`
+	LastUsedAt *time.Time  `json:"last_used_at"`
+	RevokedAt  *time.Time  `json:"revoked_at"`
+	Timestamps
+}
+
+func (k *APIKey) Active() bool {
+	return k.RevokedAt == nil
+}
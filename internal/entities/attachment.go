@@ -0,0 +1,13 @@
+package entities
+
+type Attachment struct {
+	Identifier
+	TodoID      int64  `json:"todo_id"`
+	UploadedBy  int64  `json:"uploaded_by"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+	StorageKey  string `json:"-"`
+	Timestamps
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"syreclabs.com/go/faker"
+)
+
+type Comment struct {
+	Identifier
+	TodoID    int64      `json:"todo_id"`
+	AuthorID  int64      `json:"author_id"`
+	Body      string     `json:"body"`
+	EditedAt  *time.Time `json:"edited_at"`
+	DeletedAt *time.Time `json:"-"`
+	Timestamps
+}
+
+type CommentList struct {
+	Comments   []*Comment  `json:"comments"`
+	Pagination *Pagination `json:"pagination"`
+}
+
+func BuildComment(todo *Todo, author *User) *Comment {
+	return &Comment{
+		TodoID:   todo.ID,
+		AuthorID: author.ID,
+		Body:     faker.Lorem().Paragraph(2),
+	}
+}
@@ -3,6 +3,9 @@ package entities
 type ContextKey string
 
 const (
+	ContextKeyAPIKey    ContextKey = "api_key"
+	ContextKeyIfMatch   ContextKey = "if_match"
 	ContextKeyRequestID ContextKey = "request_id"
+	ContextKeyUser      ContextKey = "user"
 	ContextKeyUserAgent ContextKey = "user_agent"
 )
@@ -1,12 +1,14 @@
 package entities
 
 type Pagination struct {
-	Count    int  `json:"count"`
-	NextPage *int `json:"next_page"`
-	NumPages int  `json:"num_pages"`
-	Page     int  `json:"page"`
-	Per      int  `json:"per"`
-	PrevPage *int `json:"prev_page"`
+	Count      int     `json:"count"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	NextPage   *int    `json:"next_page"`
+	NumPages   int     `json:"num_pages"`
+	Page       int     `json:"page"`
+	Per        int     `json:"per"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
+	PrevPage   *int    `json:"prev_page"`
 }
 
 func NewPagination(count, page, per int) *Pagination {
@@ -42,3 +44,13 @@ func NewPagination(count, page, per int) *Pagination {
 		PrevPage: prevPage,
 	}
 }
+
+func NewCursorPagination(count, limit int, nextCursor, prevCursor *string) *Pagination {
+
+	pagination := NewPagination(count, 0, limit)
+	pagination.NextPage = nil
+	pagination.NextCursor = nextCursor
+	pagination.PrevCursor = prevCursor
+
+	return pagination
+}
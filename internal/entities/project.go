@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+
+	"syreclabs.com/go/faker"
+)
+
+type Project struct {
+	Identifier
+	OwnerID        int64      `json:"owner_id"`
+	Name           string     `json:"name"`
+	ArchivedAt     *time.Time `json:"archived_at"`
+	OpenTodos      int        `json:"open_todos"`
+	CompletedTodos int        `json:"completed_todos"`
+	Timestamps
+}
+
+func (p *Project) Archived() bool {
+	return p.ArchivedAt != nil
+}
+
+func BuildProject(owner *User) *Project {
+	return &Project{
+		OwnerID: owner.ID,
+		Name:    faker.RandomString(8),
+	}
+}
@@ -0,0 +1,17 @@
+package entities
+
+import "syreclabs.com/go/faker"
+
+type Tag struct {
+	Identifier
+	OwnerID int64  `json:"owner_id"`
+	Name    string `json:"name"`
+	Timestamps
+}
+
+func BuildTag(owner *User) *Tag {
+	return &Tag{
+		OwnerID: owner.ID,
+		Name:    faker.RandomString(8),
+	}
+}
@@ -1,6 +1,7 @@
 package entities
 
 import (
+	"fmt"
 	"time"
 
 	"syreclabs.com/go/faker"
@@ -8,20 +9,61 @@ import (
 
 type Todo struct {
 	Identifier
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Completed   bool       `json:"completed"`
-	CompletedAt *time.Time `json:"completed_at"`
+	OwnerID        int64      `json:"owner_id"`
+	ProjectID      *int64     `json:"project_id"`
+	WorkspaceID    *int64     `json:"workspace_id"`
+	AssigneeID     *int64     `json:"assignee_id"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description"`
+	Status         TodoStatus `json:"status"`
+	Completed      bool       `json:"completed"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	DueAt          *time.Time `json:"due_at"`
+	Priority       Priority   `json:"priority"`
+	Tags           []*Tag     `json:"tags"`
+	ItemsTotal     int        `json:"items_total"`
+	ItemsCompleted int        `json:"items_completed"`
+	Progress       string     `json:"progress,omitempty"`
+	RecurrenceRule *string    `json:"recurrence_rule"`
+	SeriesID       *int64     `json:"series_id"`
+	SeriesStartAt  *time.Time `json:"series_start_at"`
+	Occurrence     int        `json:"occurrence"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"`
+	Version        int64      `json:"version"`
 	Timestamps
 }
 
+// SetItemCounts records how many checklist items the todo has and renders
+// them as progress, for example "3/5 done".
+func (t *Todo) SetItemCounts(counts TodoItemCounts) {
+
+	t.ItemsTotal = counts.Total
+	t.ItemsCompleted = counts.Completed
+	t.Progress = ""
+
+	if counts.Total > 0 {
+		t.Progress = fmt.Sprintf("%d/%d done", counts.Completed, counts.Total)
+	}
+}
+
+// ETag identifies the current version of the todo in HTTP caching and
+// If-Match headers.
+func (t *Todo) ETag() string {
+	return fmt.Sprintf("\"%d\"", t.Version)
+}
+
+func (t *Todo) HasOpenItems() bool {
+	return t.ItemsCompleted < t.ItemsTotal
+}
+
 type TodoList struct {
 	Todos      []*Todo     `json:"todos"`
 	Pagination *Pagination `json:"pagination"`
 }
 
-func BuildTodo() *Todo {
+func BuildTodo(owner *User) *Todo {
 	return &Todo{
+		OwnerID:     owner.ID,
 		Title:       faker.RandomString(5),
 		Description: faker.Lorem().Paragraph(2),
 	}
@@ -0,0 +1,17 @@
+package entities
+
+import "time"
+
+const (
+	TodoActivityAssigned   = "assigned"
+	TodoActivityUnassigned = "unassigned"
+)
+
+type TodoActivity struct {
+	Identifier
+	TodoID     int64     `json:"todo_id"`
+	ActorID    int64     `json:"actor_id"`
+	Action     string    `json:"action"`
+	AssigneeID *int64    `json:"assignee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
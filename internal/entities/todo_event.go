@@ -0,0 +1,104 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+const (
+	TodoEventCompleted = "completed"
+	TodoEventCreated   = "created"
+	TodoEventDeleted   = "deleted"
+	TodoEventRestored  = "restored"
+	TodoEventUpdated   = "updated"
+)
+
+// TodoEvent is one entry in the audit history of a todo. Events outlive the
+// todo they describe so the history of a deleted todo is kept.
+type TodoEvent struct {
+	Identifier
+	TodoID    int64                   `json:"todo_id"`
+	ActorID   *int64                  `json:"actor_id"`
+	Action    string                  `json:"action"`
+	RequestID string                  `json:"request_id"`
+	UserAgent string                  `json:"user_agent"`
+	Changes   map[string]*FieldChange `json:"changes"`
+	CreatedAt time.Time               `json:"created_at"`
+}
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// DiffTodos lists the fields that differ between two versions of a todo,
+// keyed by their JSON names.
+func DiffTodos(before, after *Todo) map[string]*FieldChange {
+
+	changes := make(map[string]*FieldChange)
+
+	diffValue(changes, "title", before.Title, after.Title)
+	diffValue(changes, "description", before.Description, after.Description)
+	diffValue(changes, "status", before.Status, after.Status)
+	diffValue(changes, "completed", before.Completed, after.Completed)
+	diffTime(changes, "completed_at", before.CompletedAt, after.CompletedAt)
+	diffTime(changes, "due_at", before.DueAt, after.DueAt)
+	diffValue(changes, "priority", before.Priority, after.Priority)
+	diffPointer(changes, "project_id", before.ProjectID, after.ProjectID)
+	diffPointer(changes, "workspace_id", before.WorkspaceID, after.WorkspaceID)
+	diffPointer(changes, "assignee_id", before.AssigneeID, after.AssigneeID)
+	diffPointer(changes, "recurrence_rule", before.RecurrenceRule, after.RecurrenceRule)
+	diffTime(changes, "deleted_at", before.DeletedAt, after.DeletedAt)
+
+	beforeTags, afterTags := tagNames(before.Tags), tagNames(after.Tags)
+	if !slices.Equal(beforeTags, afterTags) {
+		changes["tags"] = &FieldChange{From: beforeTags, To: afterTags}
+	}
+
+	return changes
+}
+
+func diffValue[T comparable](changes map[string]*FieldChange, field string, from, to T) {
+	if from != to {
+		changes[field] = &FieldChange{From: from, To: to}
+	}
+}
+
+func diffPointer[T comparable](changes map[string]*FieldChange, field string, from, to *T) {
+
+	if from == nil && to == nil {
+		return
+	}
+
+	if from != nil && to != nil && *from == *to {
+		return
+	}
+
+	changes[field] = &FieldChange{From: from, To: to}
+}
+
+// diffTime compares instants, so the same time in another zone is no change.
+func diffTime(changes map[string]*FieldChange, field string, from, to *time.Time) {
+
+	if from == nil && to == nil {
+		return
+	}
+
+	if from != nil && to != nil && from.Equal(*to) {
+		return
+	}
+
+	changes[field] = &FieldChange{From: from, To: to}
+}
+
+func tagNames(tags []*Tag) []string {
+
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"syreclabs.com/go/faker"
+)
+
+type TodoItem struct {
+	Identifier
+	TodoID      int64      `json:"todo_id"`
+	Title       string     `json:"title"`
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Position    int        `json:"position"`
+	Timestamps
+}
+
+type TodoItemCounts struct {
+	Completed int
+	Total     int
+}
+
+func BuildTodoItem(todo *Todo) *TodoItem {
+	return &TodoItem{
+		TodoID: todo.ID,
+		Title:  faker.RandomString(8),
+	}
+}
@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type TodoStatus string
+
+const (
+	TodoStatusBlocked    TodoStatus = "blocked"
+	TodoStatusCancelled  TodoStatus = "cancelled"
+	TodoStatusDone       TodoStatus = "done"
+	TodoStatusInProgress TodoStatus = "in_progress"
+	TodoStatusOpen       TodoStatus = "open"
+)
+
+var todoStatuses = []TodoStatus{TodoStatusOpen, TodoStatusInProgress, TodoStatusBlocked, TodoStatusDone, TodoStatusCancelled}
+
+func ParseTodoStatus(name string) (TodoStatus, error) {
+
+	status := TodoStatus(strings.ToLower(strings.TrimSpace(name)))
+	if status.Valid() {
+		return status, nil
+	}
+
+	names := make([]string, 0, len(todoStatuses))
+	for _, todoStatus := range todoStatuses {
+		names = append(names, string(todoStatus))
+	}
+
+	return "", fmt.Errorf("invalid status %v, allowed statuses are %v", name, strings.Join(names, ", "))
+}
+
+func (s TodoStatus) Valid() bool {
+	for _, status := range todoStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// SetStatus moves the todo to status and keeps the completed flag and
+// completion time, which older clients still read, in step with it.
+func (t *Todo) SetStatus(status TodoStatus, at time.Time) {
+
+	t.Status = status
+	t.Completed = status == TodoStatusDone
+
+	t.CompletedAt = nil
+	if t.Completed {
+		t.CompletedAt = &at
+	}
+}
+
+// SyncStatus reconciles the status with a completed flag that was set
+// directly, so callers that only know about completion keep working.
+func (t *Todo) SyncStatus() {
+
+	if t.Status == "" {
+		t.Status = TodoStatusOpen
+	}
+
+	if t.Completed && t.Status != TodoStatusDone {
+		t.Status = TodoStatusDone
+	} else if !t.Completed && t.Status == TodoStatusDone {
+		t.Status = TodoStatusOpen
+	}
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"strings"
+
+	"syreclabs.com/go/faker"
+)
+
+type User struct {
+	Identifier
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+	Timestamps
+}
+
+func BuildUser() *User {
+	return &User{
+		Email:        strings.ToLower(faker.Internet().Email()),
+		PasswordHash: faker.RandomString(60),
+	}
+}
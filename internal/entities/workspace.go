@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"time"
+
+	"syreclabs.com/go/faker"
+)
+
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleOwner  WorkspaceRole = "owner"
+	WorkspaceRoleEditor WorkspaceRole = "editor"
+	WorkspaceRoleViewer WorkspaceRole = "viewer"
+)
+
+func (r WorkspaceRole) CanManage() bool {
+	return r == WorkspaceRoleOwner
+}
+
+func (r WorkspaceRole) CanWrite() bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleEditor
+}
+
+func (r WorkspaceRole) Valid() bool {
+	return r == WorkspaceRoleOwner || r == WorkspaceRoleEditor || r == WorkspaceRoleViewer
+}
+
+type Workspace struct {
+	Identifier
+	Name string        `json:"name"`
+	Role WorkspaceRole `json:"role,omitempty"`
+	Timestamps
+}
+
+type WorkspaceMember struct {
+	WorkspaceID int64         `json:"workspace_id"`
+	UserID      int64         `json:"user_id"`
+	Email       string        `json:"email,omitempty"`
+	Role        WorkspaceRole `json:"role"`
+	Timestamps
+}
+
+type WorkspaceInvitation struct {
+	Identifier
+	WorkspaceID int64         `json:"workspace_id"`
+	InvitedBy   int64         `json:"invited_by"`
+	Email       string        `json:"email"`
+	Role        WorkspaceRole `json:"role"`
+	Token       string        `json:"token,omitempty"`
+	TokenHash   string        `json:"-"`
+	ExpiresAt   time.Time     `json:"expires_at"`
+	AcceptedAt  *time.Time    `json:"accepted_at"`
+	Timestamps
+}
+
+func (i *WorkspaceInvitation) Pending(now time.Time) bool {
+	return i.AcceptedAt == nil && now.Before(i.ExpiresAt)
+}
+
+func BuildWorkspace() *Workspace {
+	return &Workspace{
+		Name: faker.RandomString(8),
+	}
+}
@@ -0,0 +1,8 @@
+package forms
+
+import "github.com/ernestngugi/todo/internal/entities"
+
+type CreateAPIKeyForm struct {
+	Name  string               `json:"name" binding:"required"`
+	Scope entities.APIKeyScope `json:"scope"`
+}
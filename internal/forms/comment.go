@@ -0,0 +1,9 @@
+package forms
+
+type CreateCommentForm struct {
+	Body string `json:"body" binding:"required"`
+}
+
+type UpdateCommentForm struct {
+	Body string `json:"body" binding:"required"`
+}
@@ -0,0 +1,8 @@
+package forms
+
+type Cursor struct {
+	Backward bool     `json:"b,omitempty"`
+	ID       int64    `json:"i"`
+	Sort     string   `json:"s"`
+	Values   []string `json:"v"`
+}
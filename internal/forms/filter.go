@@ -1,13 +1,88 @@
 package forms
 
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	TagMatchAll = "all"
+	TagMatchAny = "any"
+)
+
 type Filter struct {
-	Page  int
-	Per   int
-	Valid *bool
+	AssignedToMe      bool
+	AssigneeID        int64
+	CompletedAfter    *time.Time
+	CompletedBefore   *time.Time
+	Completed         *bool
+	CreatedAfter      *time.Time
+	CreatedBefore     *time.Time
+	Cursor            *Cursor
+	CursorToken       string
+	Deleted           bool
+	DueAfter          *time.Time
+	DueBefore         *time.Time
+	IncludeArchived   bool
+	IncludeWorkspaces bool
+	Limit             int
+	Overdue           bool
+	OwnerID           int64
+	Page              int
+	Per               int
+	Priorities        []entities.Priority
+	ProjectID         int64
+	SeriesID          int64
+	Sort              []SortField
+	Statuses          []entities.TodoStatus
+	TagMatch          string
+	Tags              []string
+	Term              string
+	Unassigned        bool
+	WorkspaceID       int64
 }
 
 func (f *Filter) NoPagination() *Filter {
-	return &Filter{
-		Valid: f.Valid,
+	filter := *f
+	filter.Cursor = nil
+	filter.CursorToken = ""
+	filter.Limit = 0
+	filter.Page = 0
+	filter.Per = 0
+	return &filter
+}
+
+// Normalized returns a copy of the filter that matches the same todos with
+// its values in a fixed form, so two requests for the same page compare
+// equal however their query strings were written.
+func (f *Filter) Normalized() *Filter {
+	filter := *f
+	filter.CompletedAfter = utcTime(f.CompletedAfter)
+	filter.CompletedBefore = utcTime(f.CompletedBefore)
+	filter.CreatedAfter = utcTime(f.CreatedAfter)
+	filter.CreatedBefore = utcTime(f.CreatedBefore)
+	// the decoded cursor identifies the page, the token also carries its
+	// signature
+	filter.CursorToken = ""
+	filter.DueAfter = utcTime(f.DueAfter)
+	filter.DueBefore = utcTime(f.DueBefore)
+	filter.Priorities = slices.Clone(f.Priorities)
+	filter.Statuses = slices.Clone(f.Statuses)
+	filter.Tags = slices.Clone(f.Tags)
+	filter.Term = strings.TrimSpace(f.Term)
+	slices.Sort(filter.Priorities)
+	slices.Sort(filter.Statuses)
+	slices.Sort(filter.Tags)
+	return &filter
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
 	}
+	utc := t.UTC()
+	return &utc
 }
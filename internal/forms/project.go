@@ -0,0 +1,10 @@
+package forms
+
+type CreateProjectForm struct {
+	Name string `json:"name"`
+}
+
+type UpdateProjectForm struct {
+	Archived *bool   `json:"archived"`
+	Name     *string `json:"name"`
+}
@@ -0,0 +1,41 @@
+package forms
+
+import "strings"
+
+const (
+	SortFieldCompletedAt = "completed_at"
+	SortFieldCreatedAt   = "created_at"
+	SortFieldDueAt       = "due_at"
+	SortFieldPriority    = "priority"
+	SortFieldTitle       = "title"
+	SortFieldUpdatedAt   = "updated_at"
+)
+
+var TodoSortFields = []string{
+	SortFieldCompletedAt,
+	SortFieldCreatedAt,
+	SortFieldDueAt,
+	SortFieldPriority,
+	SortFieldTitle,
+	SortFieldUpdatedAt,
+}
+
+type SortField struct {
+	Descending bool
+	Field      string
+}
+
+func (s SortField) String() string {
+	if s.Descending {
+		return "-" + s.Field
+	}
+	return s.Field
+}
+
+func SortKey(sortFields []SortField) string {
+	fields := make([]string, 0, len(sortFields))
+	for _, sortField := range sortFields {
+		fields = append(fields, sortField.String())
+	}
+	return strings.Join(fields, ",")
+}
@@ -0,0 +1,9 @@
+package forms
+
+type CreateTagForm struct {
+	Name string `json:"name"`
+}
+
+type UpdateTagForm struct {
+	Name string `json:"name"`
+}
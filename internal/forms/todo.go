@@ -1,11 +1,55 @@
 package forms
 
+import (
+	"time"
+
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	OpenItemsCascade = "cascade"
+	OpenItemsRefuse  = "refuse"
+)
+
+// CompleteTodoForm decides what happens to checklist items that are still
+// open when a todo is completed. Completion is refused unless OpenItems is
+// OpenItemsCascade.
+type CompleteTodoForm struct {
+	OpenItems string `json:"open_items"`
+}
+
+// TransitionTodoForm moves a todo to Status. OpenItems is only used when the
+// todo is moved to done, as in CompleteTodoForm.
+type TransitionTodoForm struct {
+	OpenItems string              `json:"open_items"`
+	Status    entities.TodoStatus `json:"status" binding:"required"`
+}
+
+type AssignTodoForm struct {
+	AssigneeID int64 `json:"assignee_id" binding:"required"`
+}
+
 type CreateTodoForm struct {
-	Description string `json:"description" binding:"required"`
-	Title       string `json:"title"`
+	Description    string     `json:"description" binding:"required"`
+	DueAt          *time.Time `json:"due_at"`
+	Priority       string     `json:"priority"`
+	ProjectID      *int64     `json:"project_id"`
+	RecurrenceRule string     `json:"recurrence_rule"`
+	TagIDs         []int64    `json:"tag_ids"`
+	Title          string     `json:"title"`
+	WorkspaceID    *int64     `json:"workspace_id"`
 }
 
 type UpdateTodoForm struct {
-	Description *string `json:"description"`
-	Title       *string `json:"title"`
+	AttachTagIDs        []int64    `json:"attach_tag_ids"`
+	ClearDueAt          bool       `json:"clear_due_at"`
+	ClearProject        bool       `json:"clear_project"`
+	ClearRecurrenceRule bool       `json:"clear_recurrence_rule"`
+	DetachTagIDs        []int64    `json:"detach_tag_ids"`
+	Description         *string    `json:"description"`
+	DueAt               *time.Time `json:"due_at"`
+	Priority            *string    `json:"priority"`
+	ProjectID           *int64     `json:"project_id"`
+	RecurrenceRule      *string    `json:"recurrence_rule"`
+	Title               *string    `json:"title"`
 }
@@ -0,0 +1,9 @@
+package forms
+
+type CreateTodoItemForm struct {
+	Title string `json:"title"`
+}
+
+type ReorderTodoItemsForm struct {
+	ItemIDs []int64 `json:"item_ids" binding:"required"`
+}
@@ -0,0 +1,20 @@
+package forms
+
+import "github.com/ernestngugi/todo/internal/entities"
+
+type AcceptInvitationForm struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type CreateInvitationForm struct {
+	Email string                 `json:"email" binding:"required"`
+	Role  entities.WorkspaceRole `json:"role"`
+}
+
+type CreateWorkspaceForm struct {
+	Name string `json:"name"`
+}
+
+type UpdateMemberForm struct {
+	Role entities.WorkspaceRole `json:"role" binding:"required"`
+}
@@ -0,0 +1,48 @@
+package mocks
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/ernestngugi/todo/internal/providers"
+)
+
+type MockBlobStore struct {
+	blobs map[string][]byte
+}
+
+func NewMockBlobStore() *MockBlobStore {
+	return &MockBlobStore{
+		blobs: make(map[string][]byte),
+	}
+}
+
+func (p *MockBlobStore) Delete(key string) error {
+	delete(p.blobs, key)
+	return nil
+}
+
+func (p *MockBlobStore) Exists(key string) bool {
+	_, ok := p.blobs[key]
+	return ok
+}
+
+func (p *MockBlobStore) Get(key string) (io.ReadCloser, error) {
+	blob, ok := p.blobs[key]
+	if !ok {
+		return nil, providers.ErrBlobNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(blob)), nil
+}
+
+func (p *MockBlobStore) Put(key string, content io.Reader) (int64, error) {
+	blob, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+
+	p.blobs[key] = blob
+
+	return int64(len(blob)), nil
+}
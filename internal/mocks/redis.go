@@ -1,23 +1,52 @@
 package mocks
 
-import "github.com/gomodule/redigo/redis"
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
 
 type (
 	payload struct {
-		Value interface{}
+		Value     interface{}
+		ExpiresAt time.Time
 	}
 
+	// MockRedis keeps values in memory and expires them against its clock,
+	// which tests can replace with SetClock to move time forward.
 	MockRedis struct {
+		err   error
+		mu    sync.Mutex
+		now   func() time.Time
 		store map[string]*payload
 	}
 )
 
 func NewMockRedisProvider() *MockRedis {
 	return &MockRedis{
+		now:   time.Now,
 		store: make(map[string]*payload),
 	}
 }
 
+func (p *MockRedis) SetClock(now func() time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.now = now
+}
+
+// SetError makes every command fail with err, as if Redis were down, until
+// it is called again with nil.
+func (p *MockRedis) SetError(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.err = err
+}
+
 func (p *MockRedis) Exists(key string) (bool, error) {
 	_, err := p.Get(key)
 	if err != nil && err != redis.ErrNil {
@@ -28,7 +57,14 @@ func (p *MockRedis) Exists(key string) (bool, error) {
 }
 
 func (p *MockRedis) Get(key string) (interface{}, error) {
-	payload, ok := p.store[key]
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	payload, ok := p.live(key)
 	if !ok {
 		return nil, redis.ErrNil
 	}
@@ -36,7 +72,39 @@ func (p *MockRedis) Get(key string) (interface{}, error) {
 	return payload.Value, nil
 }
 
+// Incr keeps the expiry of an existing counter, like INCR does.
+func (p *MockRedis) Incr(key string) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return 0, p.err
+	}
+
+	counter, ok := p.live(key)
+	if !ok {
+		counter = &payload{Value: int64(0)}
+		p.store[key] = counter
+	}
+
+	value, ok := counter.Value.(int64)
+	if !ok {
+		return 0, errors.New("ERR value is not an integer or out of range")
+	}
+
+	counter.Value = value + 1
+
+	return value + 1, nil
+}
+
 func (p *MockRedis) Set(key string, val interface{}) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
 	newPayload := &payload{
 		Value: val,
 	}
@@ -46,7 +114,147 @@ func (p *MockRedis) Set(key string, val interface{}) (interface{}, error) {
 	return val, nil
 }
 
+func (p *MockRedis) SetWithTTL(key string, val interface{}, ttl time.Duration) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	newPayload := &payload{
+		Value:     val,
+		ExpiresAt: p.now().Add(ttl),
+	}
+
+	p.store[key] = newPayload
+
+	return val, nil
+}
+
+func (p *MockRedis) SetNX(key string, val interface{}, ttl time.Duration) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return false, p.err
+	}
+
+	if _, ok := p.live(key); ok {
+		return false, nil
+	}
+
+	p.store[key] = &payload{
+		Value:     val,
+		ExpiresAt: p.now().Add(ttl),
+	}
+
+	return true, nil
+}
+
+func (p *MockRedis) SetWithTTLIfLocked(key string, val interface{}, ttl time.Duration, lockKey string, token string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return false, p.err
+	}
+
+	if !p.holds(lockKey, token) {
+		return false, nil
+	}
+
+	p.store[key] = &payload{
+		Value:     val,
+		ExpiresAt: p.now().Add(ttl),
+	}
+
+	return true, nil
+}
+
+func (p *MockRedis) Unlock(lockKey string, token string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return false, p.err
+	}
+
+	if !p.holds(lockKey, token) {
+		return false, nil
+	}
+
+	delete(p.store, lockKey)
+
+	return true, nil
+}
+
+func (p *MockRedis) Expire(key string, ttl time.Duration) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return false, p.err
+	}
+
+	payload, ok := p.live(key)
+	if !ok {
+		return false, nil
+	}
+
+	payload.ExpiresAt = p.now().Add(ttl)
+
+	return true, nil
+}
+
+// TTL returns how long key has left to live, or zero when it does not exist
+// or never expires.
+func (p *MockRedis) TTL(key string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	payload, ok := p.live(key)
+	if !ok || payload.ExpiresAt.IsZero() {
+		return 0
+	}
+
+	return payload.ExpiresAt.Sub(p.now())
+}
+
 func (p *MockRedis) Del(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+
 	delete(p.store, key)
 	return nil
 }
+
+// live drops key once it has expired, the way Redis would on access.
+func (p *MockRedis) live(key string) (*payload, bool) {
+	payload, ok := p.store[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !payload.ExpiresAt.IsZero() && !p.now().Before(payload.ExpiresAt) {
+		delete(p.store, key)
+		return nil, false
+	}
+
+	return payload, true
+}
+
+func (p *MockRedis) holds(lockKey string, token string) bool {
+	payload, ok := p.live(lockKey)
+	if !ok {
+		return false
+	}
+
+	value, ok := payload.Value.(string)
+
+	return ok && value == token
+}
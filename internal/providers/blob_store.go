@@ -0,0 +1,145 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultBlobStorePath = "data/blobs"
+
+var ErrBlobNotFound = errors.New("blob not found")
+
+type (
+	BlobStore interface {
+		Delete(key string) error
+		Get(key string) (io.ReadCloser, error)
+		Put(key string, content io.Reader) (int64, error)
+	}
+
+	BlobStoreConfig struct {
+		Path string
+	}
+
+	LocalBlobStore struct {
+		root string
+	}
+)
+
+func NewBlobStoreProvider(
+	config *BlobStoreConfig,
+) *LocalBlobStore {
+
+	root := os.Getenv("BLOB_STORE_PATH")
+
+	if config != nil && config.Path != "" {
+		root = config.Path
+	}
+
+	if root == "" {
+		root = defaultBlobStorePath
+	}
+
+	return NewLocalBlobStore(root)
+}
+
+func NewLocalBlobStore(
+	root string,
+) *LocalBlobStore {
+	return &LocalBlobStore{
+		root: root,
+	}
+}
+
+// Put writes to a temporary file first so a failed or partial upload never
+// replaces an existing blob.
+func (p *LocalBlobStore) Put(
+	key string,
+	content io.Reader,
+) (int64, error) {
+
+	path, err := p.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return 0, err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.Remove(file.Name())
+
+	written, err := io.Copy(file, content)
+	if err != nil {
+		file.Close()
+		return 0, err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return 0, err
+	}
+
+	return written, nil
+}
+
+func (p *LocalBlobStore) Get(
+	key string,
+) (io.ReadCloser, error) {
+
+	path, err := p.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+
+	return file, err
+}
+
+// Delete succeeds when the blob is already gone.
+func (p *LocalBlobStore) Delete(
+	key string,
+) error {
+
+	path, err := p.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (p *LocalBlobStore) path(
+	key string,
+) (string, error) {
+
+	if key == "" || !filepath.IsLocal(key) || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("invalid blob key %v", key)
+	}
+
+	return filepath.Join(p.root, filepath.FromSlash(key)), nil
+}
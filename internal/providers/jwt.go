@@ -0,0 +1,113 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type (
+	JWT interface {
+		GenerateToken(userID int64) (string, time.Time, error)
+		ValidateToken(token string) (int64, error)
+	}
+
+	JWTConfig struct {
+		AccessTokenTTL time.Duration
+		Issuer         string
+	}
+
+	AppJWT struct {
+		accessTokenTTL time.Duration
+		issuer         string
+		secret         []byte
+	}
+)
+
+func NewJWTProvider(
+	config *JWTConfig,
+) *AppJWT {
+	return NewJWTWithSecret(os.Getenv("JWT_SECRET"), config)
+}
+
+func NewJWTWithSecret(
+	secret string,
+	config *JWTConfig,
+) *AppJWT {
+	accessTokenTTL := 15 * time.Minute
+	issuer := "todo"
+
+	if config != nil {
+		if int64(config.AccessTokenTTL) != 0 {
+			accessTokenTTL = config.AccessTokenTTL
+		}
+
+		if config.Issuer != "" {
+			issuer = config.Issuer
+		}
+	}
+
+	if secret == "" {
+		panic("jwt secret is empty")
+	}
+
+	return &AppJWT{
+		accessTokenTTL: accessTokenTTL,
+		issuer:         issuer,
+		secret:         []byte(secret),
+	}
+}
+
+func (p *AppJWT) GenerateToken(
+	userID int64,
+) (string, time.Time, error) {
+
+	issuedAt := time.Now()
+	expiresAt := issuedAt.Add(p.accessTokenTTL)
+
+	claims := jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(issuedAt),
+		Issuer:    p.issuer,
+		Subject:   strconv.FormatInt(userID, 10),
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(p.secret)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+func (p *AppJWT) ValidateToken(
+	token string,
+) (int64, error) {
+
+	var claims jwt.RegisteredClaims
+
+	_, err := jwt.ParseWithClaims(
+		token,
+		&claims,
+		func(t *jwt.Token) (interface{}, error) {
+			return p.secret, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(p.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token: %v", err)
+	}
+
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil || userID < 1 {
+		return 0, errors.New("invalid token subject")
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,369 @@
+package providers
+
+import (
+	"container/list"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMemoryCacheMaxBytes   = 64 << 20
+	defaultMemoryCacheMaxEntries = 10000
+	// memoryCacheEntryOverhead is charged on top of the key and value so
+	// that values of unknown size still count against MaxBytes
+	memoryCacheEntryOverhead = 64
+)
+
+var errNotAnInteger = errors.New("value is not an integer or out of range")
+
+type (
+	// MemoryCacheConfig limits an in-process cache. Whichever limit is
+	// reached first evicts the least recently used entries.
+	MemoryCacheConfig struct {
+		MaxBytes   int64
+		MaxEntries int
+	}
+
+	// MemoryCache is an in-process LRU cache that offers the same commands as
+	// Redis, so it can stand in for it on a single instance or sit in front
+	// of it in a TieredCache. It is safe for concurrent use.
+	MemoryCache struct {
+		bytes      int64
+		entries    map[string]*list.Element
+		maxBytes   int64
+		maxEntries int
+		mu         sync.Mutex
+		now        func() time.Time
+		recency    *list.List
+	}
+
+	memoryCacheEntry struct {
+		expiresAt time.Time
+		key       string
+		size      int64
+		value     interface{}
+	}
+)
+
+func NewMemoryCache(
+	config *MemoryCacheConfig,
+) *MemoryCache {
+
+	maxBytes := int64(defaultMemoryCacheMaxBytes)
+	maxEntries := defaultMemoryCacheMaxEntries
+
+	if config != nil {
+
+		if config.MaxBytes > 0 {
+			maxBytes = config.MaxBytes
+		}
+
+		if config.MaxEntries > 0 {
+			maxEntries = config.MaxEntries
+		}
+	}
+
+	return &MemoryCache{
+		entries:    make(map[string]*list.Element),
+		maxBytes:   maxBytes,
+		maxEntries: maxEntries,
+		now:        time.Now,
+		recency:    list.New(),
+	}
+}
+
+func (p *MemoryCache) Exists(key string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	_, ok := p.live(key)
+
+	return ok, nil
+}
+
+// Get returns nil for a missing key, like GET does.
+func (p *MemoryCache) Get(
+	key string,
+) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.live(key)
+	if !ok {
+		return nil, nil
+	}
+
+	p.recency.MoveToFront(p.entries[key])
+
+	return entry.value, nil
+}
+
+// Incr keeps the expiry of an existing counter, like INCR does.
+func (p *MemoryCache) Incr(
+	key string,
+) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var counter int64
+	var expiresAt time.Time
+
+	if entry, ok := p.live(key); ok {
+
+		value, err := integerValue(entry.value)
+		if err != nil {
+			return 0, err
+		}
+
+		counter = value
+		expiresAt = entry.expiresAt
+	}
+
+	counter++
+
+	p.store(key, counter, expiresAt)
+
+	return counter, nil
+}
+
+func (p *MemoryCache) Set(
+	key string,
+	val interface{},
+) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.store(key, val, time.Time{})
+
+	return val, nil
+}
+
+func (p *MemoryCache) SetWithTTL(
+	key string,
+	val interface{},
+	ttl time.Duration,
+) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.store(key, val, p.now().Add(ttl))
+
+	return val, nil
+}
+
+func (p *MemoryCache) SetNX(
+	key string,
+	val interface{},
+	ttl time.Duration,
+) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.live(key); ok {
+		return false, nil
+	}
+
+	p.store(key, val, p.now().Add(ttl))
+
+	return true, nil
+}
+
+func (p *MemoryCache) SetWithTTLIfLocked(
+	key string,
+	val interface{},
+	ttl time.Duration,
+	lockKey string,
+	token string,
+) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.holds(lockKey, token) {
+		return false, nil
+	}
+
+	p.store(key, val, p.now().Add(ttl))
+
+	return true, nil
+}
+
+func (p *MemoryCache) Unlock(
+	lockKey string,
+	token string,
+) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.holds(lockKey, token) {
+		return false, nil
+	}
+
+	p.remove(p.entries[lockKey])
+
+	return true, nil
+}
+
+// Expire reports false when the key does not exist.
+func (p *MemoryCache) Expire(
+	key string,
+	ttl time.Duration,
+) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.live(key)
+	if !ok {
+		return false, nil
+	}
+
+	entry.expiresAt = p.now().Add(ttl)
+
+	return true, nil
+}
+
+func (p *MemoryCache) Del(key string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if element, ok := p.entries[key]; ok {
+		p.remove(element)
+	}
+
+	return nil
+}
+
+// Flush drops every entry.
+func (p *MemoryCache) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.bytes = 0
+	p.entries = make(map[string]*list.Element)
+	p.recency.Init()
+}
+
+// Len returns how many entries the cache holds, including expired entries
+// that have not been dropped yet.
+func (p *MemoryCache) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.recency.Len()
+}
+
+// live drops key once it has expired, the way Redis would on access.
+func (p *MemoryCache) live(key string) (*memoryCacheEntry, bool) {
+
+	element, ok := p.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryCacheEntry)
+
+	if !entry.expiresAt.IsZero() && !p.now().Before(entry.expiresAt) {
+		p.remove(element)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+func (p *MemoryCache) holds(lockKey string, token string) bool {
+
+	entry, ok := p.live(lockKey)
+	if !ok {
+		return false
+	}
+
+	switch value := entry.value.(type) {
+	case string:
+		return value == token
+	case []byte:
+		return string(value) == token
+	default:
+		return false
+	}
+}
+
+func (p *MemoryCache) store(key string, val interface{}, expiresAt time.Time) {
+
+	if element, ok := p.entries[key]; ok {
+		p.remove(element)
+	}
+
+	entry := &memoryCacheEntry{
+		expiresAt: expiresAt,
+		key:       key,
+		size:      entrySize(key, val),
+		value:     val,
+	}
+
+	p.entries[key] = p.recency.PushFront(entry)
+	p.bytes += entry.size
+
+	p.evict()
+}
+
+// evict drops the least recently used entries until the cache is back
+// within its limits. Expired entries are dropped when they are next read or
+// once they reach the back of the list. The newest entry is kept even if it
+// is larger than MaxBytes on its own.
+func (p *MemoryCache) evict() {
+
+	for p.recency.Len() > 1 && (p.recency.Len() > p.maxEntries || p.bytes > p.maxBytes) {
+		p.remove(p.recency.Back())
+	}
+}
+
+func (p *MemoryCache) remove(element *list.Element) {
+
+	entry := p.recency.Remove(element).(*memoryCacheEntry)
+
+	delete(p.entries, entry.key)
+	p.bytes -= entry.size
+}
+
+func entrySize(key string, val interface{}) int64 {
+
+	size := int64(len(key) + memoryCacheEntryOverhead)
+
+	switch value := val.(type) {
+	case []byte:
+		size += int64(len(value))
+	case string:
+		size += int64(len(value))
+	}
+
+	return size
+}
+
+// integerValue reads a counter the way INCR would, whether it was stored by
+// Incr or set as a number or its text.
+func integerValue(val interface{}) (int64, error) {
+
+	switch value := val.(type) {
+	case int64:
+		return value, nil
+	case int:
+		return int64(value), nil
+	case []byte:
+		return parseInteger(string(value))
+	case string:
+		return parseInteger(value)
+	default:
+		return 0, errNotAnInteger
+	}
+}
+
+func parseInteger(value string) (int64, error) {
+
+	counter, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errNotAnInteger
+	}
+
+	return counter, nil
+}
@@ -0,0 +1,187 @@
+package providers
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMemoryCache(t *testing.T) {
+
+	Convey("TestMemoryCache", t, func() {
+
+		Convey("evicts the least recently used entry", func() {
+
+			cache := NewMemoryCache(&MemoryCacheConfig{MaxEntries: 2})
+
+			_, err := cache.Set("a", "1")
+			So(err, ShouldBeNil)
+
+			_, err = cache.Set("b", "2")
+			So(err, ShouldBeNil)
+
+			value, err := cache.Get("a")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "1")
+
+			_, err = cache.Set("c", "3")
+			So(err, ShouldBeNil)
+
+			So(cache.Len(), ShouldEqual, 2)
+
+			value, err = cache.Get("b")
+			So(err, ShouldBeNil)
+			So(value, ShouldBeNil)
+
+			value, err = cache.Get("a")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "1")
+		})
+
+		Convey("stays within its size limit", func() {
+
+			cache := NewMemoryCache(&MemoryCacheConfig{MaxBytes: 3 * (memoryCacheEntryOverhead + 101)})
+
+			for i := 0; i < 10; i++ {
+				_, err := cache.Set(fmt.Sprintf("%d", i), make([]byte, 100))
+				So(err, ShouldBeNil)
+			}
+
+			So(cache.Len(), ShouldEqual, 3)
+
+			exists, err := cache.Exists("9")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+		})
+
+		Convey("expires values after their TTL", func() {
+
+			now := time.Now()
+
+			cache := NewMemoryCache(nil)
+			cache.now = func() time.Time { return now }
+
+			_, err := cache.SetWithTTL("key", "value", time.Minute)
+			So(err, ShouldBeNil)
+
+			now = now.Add(59 * time.Second)
+
+			exists, err := cache.Exists("key")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeTrue)
+
+			now = now.Add(time.Second)
+
+			exists, err = cache.Exists("key")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+			So(cache.Len(), ShouldEqual, 0)
+		})
+
+		Convey("counts like INCR", func() {
+
+			cache := NewMemoryCache(nil)
+
+			counter, err := redis.Int64(cache.Get("counter"))
+			So(err, ShouldEqual, redis.ErrNil)
+			So(counter, ShouldEqual, 0)
+
+			counter, err = cache.Incr("counter")
+			So(err, ShouldBeNil)
+			So(counter, ShouldEqual, 1)
+
+			_, err = cache.Set("text", []byte("41"))
+			So(err, ShouldBeNil)
+
+			counter, err = cache.Incr("text")
+			So(err, ShouldBeNil)
+			So(counter, ShouldEqual, 42)
+
+			_, err = cache.Set("word", "forty")
+			So(err, ShouldBeNil)
+
+			_, err = cache.Incr("word")
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("only sets a missing key with SetNX", func() {
+
+			cache := NewMemoryCache(nil)
+
+			ok, err := cache.SetNX("lock", 1, time.Second)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = cache.SetNX("lock", 1, time.Second)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+
+			err = cache.Del("lock")
+			So(err, ShouldBeNil)
+
+			ok, err = cache.SetNX("lock", 1, time.Second)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+		})
+
+		Convey("only writes and unlocks while the lock holds the token", func() {
+
+			cache := NewMemoryCache(nil)
+
+			ok, err := cache.SetNX("key:lock", "token", time.Second)
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = cache.SetWithTTLIfLocked("key", "value", time.Minute, "key:lock", "other")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+
+			ok, err = cache.Unlock("key:lock", "other")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeFalse)
+
+			ok, err = cache.SetWithTTLIfLocked("key", "value", time.Minute, "key:lock", "token")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			ok, err = cache.Unlock("key:lock", "token")
+			So(err, ShouldBeNil)
+			So(ok, ShouldBeTrue)
+
+			exists, err := cache.Exists("key:lock")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+		})
+
+		Convey("can be used concurrently", func() {
+
+			cache := NewMemoryCache(&MemoryCacheConfig{MaxEntries: 50})
+
+			var wg sync.WaitGroup
+
+			for i := 0; i < 8; i++ {
+
+				wg.Add(1)
+
+				go func(i int) {
+					defer wg.Done()
+
+					for j := 0; j < 200; j++ {
+						key := fmt.Sprintf("%d", (i*j)%80)
+						cache.SetWithTTL(key, []byte(key), time.Minute)
+						cache.Get(key)
+						cache.Incr("counter")
+					}
+				}(i)
+			}
+
+			wg.Wait()
+
+			So(cache.Len(), ShouldBeLessThanOrEqualTo, 50)
+		})
+	})
+}
@@ -1,6 +1,8 @@
 package providers
 
 import (
+	"context"
+	"expvar"
 	"fmt"
 	"os"
 	"time"
@@ -8,12 +10,36 @@ import (
 	"github.com/gomodule/redigo/redis"
 )
 
+var (
+	// CacheErrors counts the cache failures requests carried on through, by
+	// operation.
+	CacheErrors = expvar.NewMap("cache_errors")
+
+	setWithTTLIfLockedScript = redis.NewScript(2, `if redis.call("GET", KEYS[2]) == ARGV[1] then redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3]) return 1 end return 0`)
+	unlockScript             = redis.NewScript(1, `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`)
+)
+
 type (
 	Redis interface {
 		Del(key string) error
 		Exists(key string) (bool, error)
+		Expire(key string, ttl time.Duration) (bool, error)
 		Get(key string) (interface{}, error)
+		Incr(key string) (int64, error)
 		Set(key string, val interface{}) (interface{}, error)
+		SetNX(key string, val interface{}, ttl time.Duration) (bool, error)
+		SetWithTTL(key string, val interface{}, ttl time.Duration) (interface{}, error)
+		SetWithTTLIfLocked(key string, val interface{}, ttl time.Duration, lockKey string, token string) (bool, error)
+		Unlock(lockKey string, token string) (bool, error)
+	}
+
+	// PubSub delivers messages to every instance subscribed to a channel.
+	PubSub interface {
+		Publish(channel string, message string) error
+		// Subscribe calls subscribed once the subscription is in place and
+		// handle for every message after that, until ctx is done or the
+		// connection fails.
+		Subscribe(ctx context.Context, channel string, subscribed func(), handle func(message string)) error
 	}
 
 	RedisConfig struct {
@@ -84,6 +110,14 @@ func (p *AppRedis) Get(
 	return p.do("GET", key)
 }
 
+// Incr adds one to the counter at key, starting from zero when it does not
+// exist, and returns the new value.
+func (p *AppRedis) Incr(
+	key string,
+) (int64, error) {
+	return redis.Int64(p.do("INCR", key))
+}
+
 func (p *AppRedis) Set(
 	key string,
 	val interface{},
@@ -91,11 +125,132 @@ func (p *AppRedis) Set(
 	return p.do("SET", key, val)
 }
 
+// SetWithTTL stores val and lets Redis drop it once ttl has passed. The ttl
+// is sent in milliseconds.
+func (p *AppRedis) SetWithTTL(
+	key string,
+	val interface{},
+	ttl time.Duration,
+) (interface{}, error) {
+	return p.do("SET", key, val, "PX", ttl.Milliseconds())
+}
+
+// SetNX only stores val when key does not exist yet and reports whether it
+// did, which makes it usable as a lock that expires after ttl.
+func (p *AppRedis) SetNX(
+	key string,
+	val interface{},
+	ttl time.Duration,
+) (bool, error) {
+
+	reply, err := p.do("SET", key, val, "NX", "PX", ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+
+	return reply != nil, nil
+}
+
+// SetWithTTLIfLocked stores val only while lockKey still holds token, taken
+// with SetNX, so a holder that lost its lock cannot write any more.
+func (p *AppRedis) SetWithTTLIfLocked(
+	key string,
+	val interface{},
+	ttl time.Duration,
+	lockKey string,
+	token string,
+) (bool, error) {
+	return redis.Bool(p.script(setWithTTLIfLockedScript, key, lockKey, token, val, ttl.Milliseconds()))
+}
+
+// Unlock releases a lock taken with SetNX, unless it expired and was taken
+// by someone else in the meantime.
+func (p *AppRedis) Unlock(
+	lockKey string,
+	token string,
+) (bool, error) {
+	return redis.Bool(p.script(unlockScript, lockKey, token))
+}
+
+// Expire reports false when the key does not exist.
+func (p *AppRedis) Expire(
+	key string,
+	ttl time.Duration,
+) (bool, error) {
+	return redis.Bool(p.do("PEXPIRE", key, ttl.Milliseconds()))
+}
+
 func (p *AppRedis) Del(key string) error {
 	_, err := p.do("DEL", key)
 	return err
 }
 
+func (p *AppRedis) Publish(
+	channel string,
+	message string,
+) error {
+	_, err := p.do("PUBLISH", channel, message)
+	return err
+}
+
+// Subscribe holds a connection of the pool for as long as it runs.
+func (p *AppRedis) Subscribe(
+	ctx context.Context,
+	channel string,
+	subscribed func(),
+	handle func(message string),
+) error {
+
+	conn := redis.PubSubConn{Conn: p.pool.Get()}
+	defer conn.Close()
+
+	err := conn.Subscribe(channel)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan error, 1)
+
+	go func() {
+		for {
+			switch reply := conn.Receive().(type) {
+			case redis.Message:
+				handle(string(reply.Data))
+			case redis.Subscription:
+				if reply.Kind == "subscribe" {
+					subscribed()
+				} else if reply.Count == 0 {
+					done <- nil
+					return
+				}
+			case error:
+				done <- reply
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		err = conn.Unsubscribe()
+		if err != nil {
+			return err
+		}
+		return <-done
+	case err = <-done:
+		return err
+	}
+}
+
+func (p *AppRedis) script(
+	script *redis.Script,
+	keysAndArgs ...interface{},
+) (interface{}, error) {
+	conn := p.pool.Get()
+	defer conn.Close()
+	return script.Do(conn, keysAndArgs...)
+}
+
 func (p *AppRedis) do(
 	commandName string,
 	args ...interface{},
@@ -0,0 +1,330 @@
+package providers
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultTieredCacheChannel = "cache-invalidations"
+	defaultTieredCacheL1TTL   = 30 * time.Second
+	tieredCacheRetryInterval  = time.Second
+)
+
+type (
+	// SharedCache is a cache every instance reaches, which can also tell the
+	// other instances about changes.
+	SharedCache interface {
+		Redis
+		PubSub
+	}
+
+	TieredCacheConfig struct {
+		Channel string
+		L1      *MemoryCacheConfig
+		// L1TTL bounds how long an instance serves a value from memory
+		// without asking L2.
+		L1TTL time.Duration
+		// SharedOnly picks keys that are kept in L2 alone, such as locks and
+		// counters, which change too often to be worth keeping in L1.
+		SharedOnly func(key string) bool
+	}
+
+	// TieredCache keeps recently read values in memory (L1) in front of a
+	// shared cache (L2). Writes go to L2 and every instance drops its L1 copy
+	// of the key when the change is published, so L1 is only used while the
+	// invalidation subscription started by Run is up.
+	TieredCache struct {
+		channel string
+		// invalidations counts the invalidations received, so a read can
+		// tell that its key may have changed while it was reading L2
+		invalidations atomic.Uint64
+		l1            *MemoryCache
+		l1TTL         time.Duration
+		l2            SharedCache
+		sharedOnly    func(key string) bool
+		// storing, when set, runs between a read's check of invalidations
+		// and its L1 write, so tests can land an invalidation there
+		storing    func()
+		subscribed atomic.Bool
+	}
+)
+
+func NewTieredCache(
+	config *TieredCacheConfig,
+	l2 SharedCache,
+) *TieredCache {
+
+	channel := defaultTieredCacheChannel
+	l1TTL := defaultTieredCacheL1TTL
+
+	sharedOnly := func(key string) bool { return false }
+
+	var l1Config *MemoryCacheConfig
+
+	if config != nil {
+
+		if config.Channel != "" {
+			channel = config.Channel
+		}
+
+		if config.L1TTL > 0 {
+			l1TTL = config.L1TTL
+		}
+
+		if config.SharedOnly != nil {
+			sharedOnly = config.SharedOnly
+		}
+
+		l1Config = config.L1
+	}
+
+	return &TieredCache{
+		channel:    channel,
+		l1:         NewMemoryCache(l1Config),
+		l1TTL:      l1TTL,
+		l2:         l2,
+		sharedOnly: sharedOnly,
+	}
+}
+
+// Run keeps the invalidation subscription up until ctx is done, subscribing
+// again after a failure.
+func (p *TieredCache) Run(ctx context.Context) {
+
+	for {
+
+		err := p.l2.Subscribe(ctx, p.channel, p.resume, p.invalidateLocal)
+
+		p.subscribed.Store(false)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Printf("cache invalidation subscription error: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(tieredCacheRetryInterval):
+		}
+	}
+}
+
+func (p *TieredCache) Exists(key string) (bool, error) {
+
+	if p.inL1(key) {
+
+		exists, _ := p.l1.Exists(key)
+		if exists {
+			return true, nil
+		}
+	}
+
+	return p.l2.Exists(key)
+}
+
+func (p *TieredCache) Get(
+	key string,
+) (interface{}, error) {
+
+	if !p.inL1(key) {
+		return p.l2.Get(key)
+	}
+
+	value, _ := p.l1.Get(key)
+	if value != nil {
+		return value, nil
+	}
+
+	invalidations := p.invalidations.Load()
+
+	value, err := p.l2.Get(key)
+	if err != nil || value == nil {
+		return value, err
+	}
+
+	// a value read while an invalidation arrived may already be stale
+	if !p.unchangedSince(invalidations) {
+		return value, nil
+	}
+
+	if p.storing != nil {
+		p.storing()
+	}
+
+	p.l1.SetWithTTL(key, value, p.l1TTL)
+
+	// an invalidation that arrived after the check above may have dropped
+	// the key before it was stored
+	if !p.unchangedSince(invalidations) {
+		p.l1.Del(key)
+	}
+
+	return value, nil
+}
+
+func (p *TieredCache) Incr(
+	key string,
+) (int64, error) {
+
+	counter, err := p.l2.Incr(key)
+	if err != nil {
+		return 0, err
+	}
+
+	p.invalidate(key)
+
+	return counter, nil
+}
+
+func (p *TieredCache) Set(
+	key string,
+	val interface{},
+) (interface{}, error) {
+
+	reply, err := p.l2.Set(key, val)
+	if err != nil {
+		return nil, err
+	}
+
+	p.invalidate(key)
+
+	return reply, nil
+}
+
+func (p *TieredCache) SetWithTTL(
+	key string,
+	val interface{},
+	ttl time.Duration,
+) (interface{}, error) {
+
+	reply, err := p.l2.SetWithTTL(key, val, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	p.invalidate(key)
+
+	return reply, nil
+}
+
+func (p *TieredCache) SetNX(
+	key string,
+	val interface{},
+	ttl time.Duration,
+) (bool, error) {
+
+	ok, err := p.l2.SetNX(key, val, ttl)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	p.invalidate(key)
+
+	return true, nil
+}
+
+func (p *TieredCache) SetWithTTLIfLocked(
+	key string,
+	val interface{},
+	ttl time.Duration,
+	lockKey string,
+	token string,
+) (bool, error) {
+
+	ok, err := p.l2.SetWithTTLIfLocked(key, val, ttl, lockKey, token)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	p.invalidate(key)
+
+	return true, nil
+}
+
+// Unlock leaves L1 alone, locks are only ever read from L2.
+func (p *TieredCache) Unlock(
+	lockKey string,
+	token string,
+) (bool, error) {
+	return p.l2.Unlock(lockKey, token)
+}
+
+func (p *TieredCache) Expire(
+	key string,
+	ttl time.Duration,
+) (bool, error) {
+
+	ok, err := p.l2.Expire(key, ttl)
+	if err != nil || !ok {
+		return ok, err
+	}
+
+	p.invalidate(key)
+
+	return true, nil
+}
+
+func (p *TieredCache) Del(key string) error {
+
+	err := p.l2.Del(key)
+	if err != nil {
+		return err
+	}
+
+	p.invalidate(key)
+
+	return nil
+}
+
+// invalidate drops key from L1 here straight away and from every other
+// instance once they receive the message. The write already went through
+// L2, so a failed publish is only recorded; other instances then serve their
+// copy until it expires from L1.
+func (p *TieredCache) invalidate(key string) {
+
+	if p.sharedOnly(key) {
+		return
+	}
+
+	p.invalidateLocal(key)
+
+	err := p.l2.Publish(p.channel, key)
+	if err != nil {
+		CacheErrors.Add("publish", 1)
+		log.Printf("cache publish error: %v", err)
+	}
+}
+
+// inL1 reports whether key is read through L1.
+func (p *TieredCache) inL1(key string) bool {
+	return p.subscribed.Load() && !p.sharedOnly(key)
+}
+
+// unchangedSince reports whether L1 is in use and has seen no invalidation
+// since the count was taken.
+func (p *TieredCache) unchangedSince(invalidations uint64) bool {
+	return p.subscribed.Load() && p.invalidations.Load() == invalidations
+}
+
+func (p *TieredCache) invalidateLocal(key string) {
+
+	p.invalidations.Add(1)
+
+	p.l1.Del(key)
+}
+
+// resume starts using L1 again once subscribed. Whatever it held may have
+// missed invalidations while the subscription was down.
+func (p *TieredCache) resume() {
+
+	p.invalidations.Add(1)
+
+	p.l1.Flush()
+
+	p.subscribed.Store(true)
+}
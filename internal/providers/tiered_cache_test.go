@@ -0,0 +1,241 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// sharedMemoryCache stands in for Redis with publish and subscribe.
+type sharedMemoryCache struct {
+	*MemoryCache
+	handlers   map[*func(message string)]bool
+	mu         sync.Mutex
+	publishErr error
+	published  int
+}
+
+func (p *sharedMemoryCache) Publish(channel string, message string) error {
+	p.mu.Lock()
+
+	if p.publishErr != nil {
+		p.mu.Unlock()
+		return p.publishErr
+	}
+
+	p.published++
+
+	handlers := make([]func(message string), 0, len(p.handlers))
+	for handle := range p.handlers {
+		handlers = append(handlers, *handle)
+	}
+
+	p.mu.Unlock()
+
+	for _, handle := range handlers {
+		handle(message)
+	}
+
+	return nil
+}
+
+func (p *sharedMemoryCache) Subscribe(ctx context.Context, channel string, subscribed func(), handle func(message string)) error {
+	p.mu.Lock()
+	p.handlers[&handle] = true
+	p.mu.Unlock()
+
+	subscribed()
+
+	<-ctx.Done()
+
+	p.mu.Lock()
+	delete(p.handlers, &handle)
+	p.mu.Unlock()
+
+	return nil
+}
+
+func TestTieredCache(t *testing.T) {
+
+	Convey("TestTieredCache", t, func() {
+
+		redisProvider := &sharedMemoryCache{
+			MemoryCache: NewMemoryCache(nil),
+			handlers:    make(map[*func(message string)]bool),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// two instances sharing one Redis
+		instance1 := NewTieredCache(nil, redisProvider)
+		instance2 := NewTieredCache(nil, redisProvider)
+
+		for _, instance := range []*TieredCache{instance1, instance2} {
+
+			go instance.Run(ctx)
+
+			for !instance.subscribed.Load() {
+				time.Sleep(time.Millisecond)
+			}
+		}
+
+		Convey("serves repeated reads from memory", func() {
+
+			_, err := instance1.SetWithTTL("key", []byte("value"), time.Minute)
+			So(err, ShouldBeNil)
+
+			value, err := instance1.Get("key")
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, []byte("value"))
+
+			// behind the cache's back, so no invalidation is published
+			_, err = redisProvider.Set("key", []byte("changed"))
+			So(err, ShouldBeNil)
+
+			value, err = instance1.Get("key")
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, []byte("value"))
+		})
+
+		Convey("drops the memory copy on every instance when a key changes", func() {
+
+			_, err := instance1.SetWithTTL("key", []byte("value"), time.Minute)
+			So(err, ShouldBeNil)
+
+			value, err := instance2.Get("key")
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, []byte("value"))
+
+			_, err = instance1.SetWithTTL("key", []byte("changed"), time.Minute)
+			So(err, ShouldBeNil)
+
+			value, err = instance2.Get("key")
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, []byte("changed"))
+
+			err = instance1.Del("key")
+			So(err, ShouldBeNil)
+
+			exists, err := instance2.Exists("key")
+			So(err, ShouldBeNil)
+			So(exists, ShouldBeFalse)
+		})
+
+		Convey("does not keep a value invalidated just before it is stored in memory", func() {
+
+			_, err := instance1.SetWithTTL("key", []byte("value"), time.Minute)
+			So(err, ShouldBeNil)
+
+			instance2.storing = func() {
+				instance2.storing = nil
+
+				_, err := instance1.SetWithTTL("key", []byte("changed"), time.Minute)
+				So(err, ShouldBeNil)
+			}
+
+			value, err := instance2.Get("key")
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, []byte("value"))
+
+			value, err = instance2.Get("key")
+			So(err, ShouldBeNil)
+			So(value, ShouldResemble, []byte("changed"))
+		})
+
+		Convey("reads counters incremented on another instance", func() {
+
+			_, err := instance1.Incr("counter")
+			So(err, ShouldBeNil)
+
+			value, err := instance2.Get("counter")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, int64(1))
+
+			_, err = instance1.Incr("counter")
+			So(err, ShouldBeNil)
+
+			value, err = instance2.Get("counter")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, int64(2))
+		})
+
+		Convey("only reads from Redis while it is not subscribed", func() {
+
+			instance := NewTieredCache(nil, redisProvider)
+
+			_, err := instance.SetWithTTL("key", []byte("value"), time.Minute)
+			So(err, ShouldBeNil)
+
+			_, err = instance.Get("key")
+			So(err, ShouldBeNil)
+
+			So(instance.l1.Len(), ShouldEqual, 0)
+		})
+
+		Convey("reports a write that reached Redis even if publishing fails", func() {
+
+			failures := func() int64 {
+				counter, _ := CacheErrors.Get("publish").(*expvar.Int)
+				if counter == nil {
+					return 0
+				}
+				return counter.Value()
+			}
+
+			before := failures()
+
+			redisProvider.mu.Lock()
+			redisProvider.publishErr = errors.New("connection reset")
+			redisProvider.mu.Unlock()
+
+			locked, err := instance1.SetNX("key", "value", time.Minute)
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+
+			err = instance1.Del("key")
+			So(err, ShouldBeNil)
+
+			So(failures(), ShouldEqual, before+2)
+		})
+
+		Convey("keeps shared-only keys out of memory without publishing their changes", func() {
+
+			instance := NewTieredCache(&TieredCacheConfig{
+				SharedOnly: func(key string) bool { return strings.HasSuffix(key, ":lock") },
+			}, redisProvider)
+
+			go instance.Run(ctx)
+
+			for !instance.subscribed.Load() {
+				time.Sleep(time.Millisecond)
+			}
+
+			locked, err := instance.SetNX("key:lock", "token", time.Minute)
+			So(err, ShouldBeNil)
+			So(locked, ShouldBeTrue)
+
+			value, err := instance.Get("key:lock")
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, "token")
+
+			unlocked, err := instance.Unlock("key:lock", "token")
+			So(err, ShouldBeNil)
+			So(unlocked, ShouldBeTrue)
+
+			So(instance.l1.Len(), ShouldEqual, 0)
+
+			redisProvider.mu.Lock()
+			published := redisProvider.published
+			redisProvider.mu.Unlock()
+
+			So(published, ShouldEqual, 0)
+		})
+	})
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	getAPIKeyByHashSQL   = selectAPIKeySQL + " WHERE key_hash = $1"
+	getAPIKeyByIDSQL     = selectAPIKeySQL + " WHERE id = $1"
+	getAPIKeysByUserSQL  = selectAPIKeySQL + " WHERE user_id = $1 ORDER BY id"
+	insertAPIKeySQL      = "INSERT INTO api_keys (user_id, name, prefix, key_hash, scope, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+	selectAPIKeySQL      = "SELECT id, user_id, name, prefix, key_hash, scope, last_used_at, revoked_at, created_at, updated_at FROM api_keys"
+	updateAPIKeySQL      = "UPDATE api_keys SET name = $1, scope = $2, revoked_at = $3, updated_at = $4 WHERE id = $5"
+	updateAPIKeyUsageSQL = "UPDATE api_keys SET last_used_at = $1 WHERE id = $2"
+)
+
+type (
+	APIKeyRepository interface {
+		APIKeyByHash(ctx context.Context, operations db.SQLOperations, keyHash string) (*entities.APIKey, error)
+		APIKeyByID(ctx context.Context, operations db.SQLOperations, apiKeyID int64) (*entities.APIKey, error)
+		APIKeysByUser(ctx context.Context, operations db.SQLOperations, userID int64) ([]*entities.APIKey, error)
+		Save(ctx context.Context, operations db.SQLOperations, apiKey *entities.APIKey) error
+		UpdateLastUsed(ctx context.Context, operations db.SQLOperations, apiKey *entities.APIKey) error
+	}
+
+	apiKeyRepository struct{}
+)
+
+func NewAPIKeyRepository() APIKeyRepository {
+	return &apiKeyRepository{}
+}
+
+func (r *apiKeyRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	apiKey *entities.APIKey,
+) error {
+
+	apiKey.Touch()
+
+	if apiKey.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertAPIKeySQL,
+			apiKey.UserID,
+			apiKey.Name,
+			apiKey.Prefix,
+			apiKey.KeyHash,
+			apiKey.Scope,
+			apiKey.CreatedAt,
+			apiKey.UpdatedAt,
+		).Scan(&apiKey.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateAPIKeySQL,
+		apiKey.Name,
+		apiKey.Scope,
+		apiKey.RevokedAt,
+		apiKey.UpdatedAt,
+		apiKey.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) UpdateLastUsed(
+	ctx context.Context,
+	operations db.SQLOperations,
+	apiKey *entities.APIKey,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateAPIKeyUsageSQL,
+		apiKey.LastUsedAt,
+		apiKey.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *apiKeyRepository) APIKeyByHash(
+	ctx context.Context,
+	operations db.SQLOperations,
+	keyHash string,
+) (*entities.APIKey, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getAPIKeyByHashSQL,
+		keyHash,
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *apiKeyRepository) APIKeyByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	apiKeyID int64,
+) (*entities.APIKey, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getAPIKeyByIDSQL,
+		apiKeyID,
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *apiKeyRepository) APIKeysByUser(
+	ctx context.Context,
+	operations db.SQLOperations,
+	userID int64,
+) ([]*entities.APIKey, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getAPIKeysByUserSQL,
+		userID,
+	)
+	if err != nil {
+		return []*entities.APIKey{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	apiKeys := make([]*entities.APIKey, 0)
+
+	for rows.Next() {
+		apiKey, err := r.scanRow(rows)
+		if err != nil {
+			return []*entities.APIKey{}, err
+		}
+
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.APIKey{}, apperror.NewDatabaseError(err)
+	}
+
+	return apiKeys, nil
+}
+
+func (r *apiKeyRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.APIKey, error) {
+
+	var apiKey entities.APIKey
+
+	err := rowScanner.Scan(
+		&apiKey.ID,
+		&apiKey.UserID,
+		&apiKey.Name,
+		&apiKey.Prefix,
+		&apiKey.KeyHash,
+		&apiKey.Scope,
+		&apiKey.LastUsedAt,
+		&apiKey.RevokedAt,
+		&apiKey.CreatedAt,
+		&apiKey.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.APIKey{}, apperror.NewDatabaseError(err)
+	}
+
+	return &apiKey, nil
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestAPIKeyRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestAPIKeyRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		apiKeyRepository := NewAPIKeyRepository()
+
+		user, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		apiKey := &entities.APIKey{
+			UserID:  user.ID,
+			Name:    "ci",
+			Prefix:  "todo_abcdefg",
+			KeyHash: "hash",
+			Scope:   entities.APIKeyScopeRead,
+		}
+
+		Convey("can save an api key", func() {
+
+			err := apiKeyRepository.Save(ctx, dB, apiKey)
+			So(err, ShouldBeNil)
+
+			So(apiKey.ID, ShouldNotBeZeroValue)
+		})
+
+		Convey("can get an api key by hash", func() {
+
+			err := apiKeyRepository.Save(ctx, dB, apiKey)
+			So(err, ShouldBeNil)
+
+			foundAPIKey, err := apiKeyRepository.APIKeyByHash(ctx, dB, "hash")
+			So(err, ShouldBeNil)
+
+			So(foundAPIKey.ID, ShouldEqual, apiKey.ID)
+			So(foundAPIKey.Scope, ShouldEqual, entities.APIKeyScopeRead)
+			So(foundAPIKey.RevokedAt, ShouldBeNil)
+		})
+
+		Convey("can revoke an api key and record its usage", func() {
+
+			err := apiKeyRepository.Save(ctx, dB, apiKey)
+			So(err, ShouldBeNil)
+
+			timeNow := time.Now()
+			apiKey.RevokedAt = &timeNow
+			apiKey.LastUsedAt = &timeNow
+
+			err = apiKeyRepository.Save(ctx, dB, apiKey)
+			So(err, ShouldBeNil)
+
+			err = apiKeyRepository.UpdateLastUsed(ctx, dB, apiKey)
+			So(err, ShouldBeNil)
+
+			foundAPIKey, err := apiKeyRepository.APIKeyByID(ctx, dB, apiKey.ID)
+			So(err, ShouldBeNil)
+
+			So(foundAPIKey.RevokedAt, ShouldNotBeNil)
+			So(foundAPIKey.LastUsedAt, ShouldNotBeNil)
+		})
+
+		Convey("can list api keys of a user", func() {
+
+			err := apiKeyRepository.Save(ctx, dB, apiKey)
+			So(err, ShouldBeNil)
+
+			apiKeys, err := apiKeyRepository.APIKeysByUser(ctx, dB, user.ID)
+			So(err, ShouldBeNil)
+
+			So(len(apiKeys), ShouldEqual, 1)
+			So(apiKeys[0].ID, ShouldEqual, apiKey.ID)
+		})
+	}))
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/lib/pq"
+)
+
+const (
+	deleteAttachmentSQL  = "DELETE FROM attachments WHERE id = $1"
+	getAttachmentByIDSQL = selectAttachmentSQL + " WHERE id = $1"
+	getAttachmentsSQL    = selectAttachmentSQL + " WHERE todo_id = $1 ORDER BY created_at, id"
+	getStorageKeysSQL    = "SELECT todo_id, storage_key FROM attachments WHERE todo_id = ANY($1)"
+	insertAttachmentSQL  = "INSERT INTO attachments (todo_id, uploaded_by, file_name, content_type, size, checksum, storage_key, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id"
+	selectAttachmentSQL  = "SELECT id, todo_id, uploaded_by, file_name, content_type, size, checksum, storage_key, created_at, updated_at FROM attachments"
+)
+
+type (
+	AttachmentRepository interface {
+		AttachmentByID(ctx context.Context, operations db.SQLOperations, attachmentID int64) (*entities.Attachment, error)
+		Attachments(ctx context.Context, operations db.SQLOperations, todoID int64) ([]*entities.Attachment, error)
+		DeleteAttachment(ctx context.Context, operations db.SQLOperations, attachmentID int64) error
+		Save(ctx context.Context, operations db.SQLOperations, attachment *entities.Attachment) error
+		StorageKeysForTodos(ctx context.Context, operations db.SQLOperations, todoIDs []int64) (map[int64][]string, error)
+	}
+
+	attachmentRepository struct{}
+)
+
+func NewAttachmentRepository() AttachmentRepository {
+	return &attachmentRepository{}
+}
+
+// Save only inserts; an uploaded file is replaced by uploading a new one.
+func (r *attachmentRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	attachment *entities.Attachment,
+) error {
+
+	attachment.Touch()
+
+	err := operations.QueryRowContext(
+		ctx,
+		insertAttachmentSQL,
+		attachment.TodoID,
+		attachment.UploadedBy,
+		attachment.FileName,
+		attachment.ContentType,
+		attachment.Size,
+		attachment.Checksum,
+		attachment.StorageKey,
+		attachment.CreatedAt,
+		attachment.UpdatedAt,
+	).Scan(&attachment.ID)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *attachmentRepository) AttachmentByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	attachmentID int64,
+) (*entities.Attachment, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getAttachmentByIDSQL,
+		attachmentID,
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *attachmentRepository) Attachments(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+) ([]*entities.Attachment, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getAttachmentsSQL,
+		todoID,
+	)
+	if err != nil {
+		return []*entities.Attachment{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	attachments := make([]*entities.Attachment, 0)
+
+	for rows.Next() {
+		attachment, err := r.scanRow(rows)
+		if err != nil {
+			return []*entities.Attachment{}, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.Attachment{}, apperror.NewDatabaseError(err)
+	}
+
+	return attachments, nil
+}
+
+// StorageKeysForTodos returns the storage keys of the todos' attachments by
+// todo.
+func (r *attachmentRepository) StorageKeysForTodos(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoIDs []int64,
+) (map[int64][]string, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getStorageKeysSQL,
+		pq.Array(todoIDs),
+	)
+	if err != nil {
+		return map[int64][]string{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	storageKeys := make(map[int64][]string)
+
+	for rows.Next() {
+
+		var todoID int64
+		var storageKey string
+
+		err := rows.Scan(&todoID, &storageKey)
+		if err != nil {
+			return map[int64][]string{}, apperror.NewDatabaseError(err)
+		}
+
+		storageKeys[todoID] = append(storageKeys[todoID], storageKey)
+	}
+
+	if err := rows.Err(); err != nil {
+		return map[int64][]string{}, apperror.NewDatabaseError(err)
+	}
+
+	return storageKeys, nil
+}
+
+func (r *attachmentRepository) DeleteAttachment(
+	ctx context.Context,
+	operations db.SQLOperations,
+	attachmentID int64,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		deleteAttachmentSQL,
+		attachmentID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *attachmentRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.Attachment, error) {
+
+	var attachment entities.Attachment
+
+	err := rowScanner.Scan(
+		&attachment.ID,
+		&attachment.TodoID,
+		&attachment.UploadedBy,
+		&attachment.FileName,
+		&attachment.ContentType,
+		&attachment.Size,
+		&attachment.Checksum,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+		&attachment.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.Attachment{}, apperror.NewDatabaseError(err)
+	}
+
+	return &attachment, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	countCommentsSQL  = "SELECT COUNT(id) FROM comments WHERE todo_id = $1 AND deleted_at IS NULL"
+	getCommentByIDSQL = selectCommentSQL + " WHERE id = $1 AND deleted_at IS NULL"
+	getCommentsSQL    = selectCommentSQL + " WHERE todo_id = $1 AND deleted_at IS NULL ORDER BY created_at, id LIMIT $2 OFFSET $3"
+	insertCommentSQL  = "INSERT INTO comments (todo_id, author_id, body, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	selectCommentSQL  = "SELECT id, todo_id, author_id, body, edited_at, deleted_at, created_at, updated_at FROM comments"
+	updateCommentSQL  = "UPDATE comments SET body = $1, edited_at = $2, deleted_at = $3, updated_at = $4 WHERE id = $5"
+)
+
+type (
+	CommentRepository interface {
+		CommentByID(ctx context.Context, operations db.SQLOperations, commentID int64) (*entities.Comment, error)
+		Comments(ctx context.Context, operations db.SQLOperations, todoID int64, page, per int) ([]*entities.Comment, error)
+		NumberOfComments(ctx context.Context, operations db.SQLOperations, todoID int64) (int, error)
+		Save(ctx context.Context, operations db.SQLOperations, comment *entities.Comment) error
+	}
+
+	commentRepository struct{}
+)
+
+func NewCommentRepository() CommentRepository {
+	return &commentRepository{}
+}
+
+func (r *commentRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	comment *entities.Comment,
+) error {
+
+	comment.Touch()
+
+	if comment.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertCommentSQL,
+			comment.TodoID,
+			comment.AuthorID,
+			comment.Body,
+			comment.CreatedAt,
+			comment.UpdatedAt,
+		).Scan(&comment.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateCommentSQL,
+		comment.Body,
+		comment.EditedAt,
+		comment.DeletedAt,
+		comment.UpdatedAt,
+		comment.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *commentRepository) CommentByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	commentID int64,
+) (*entities.Comment, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getCommentByIDSQL,
+		commentID,
+	)
+
+	return r.scanRow(row)
+}
+
+// Comments returns a page of a todo's comments, oldest first so the thread
+// reads top to bottom.
+func (r *commentRepository) Comments(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+	page int,
+	per int,
+) ([]*entities.Comment, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getCommentsSQL,
+		todoID,
+		per,
+		(page-1)*per,
+	)
+	if err != nil {
+		return []*entities.Comment{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	comments := make([]*entities.Comment, 0)
+
+	for rows.Next() {
+		comment, err := r.scanRow(rows)
+		if err != nil {
+			return []*entities.Comment{}, err
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.Comment{}, apperror.NewDatabaseError(err)
+	}
+
+	return comments, nil
+}
+
+func (r *commentRepository) NumberOfComments(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+) (int, error) {
+
+	var count int
+
+	err := operations.QueryRowContext(
+		ctx,
+		countCommentsSQL,
+		todoID,
+	).Scan(&count)
+	if err != nil {
+		return 0, apperror.NewDatabaseError(err)
+	}
+
+	return count, nil
+}
+
+func (r *commentRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.Comment, error) {
+
+	var comment entities.Comment
+
+	err := rowScanner.Scan(
+		&comment.ID,
+		&comment.TodoID,
+		&comment.AuthorID,
+		&comment.Body,
+		&comment.EditedAt,
+		&comment.DeletedAt,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.Comment{}, apperror.NewDatabaseError(err)
+	}
+
+	return &comment, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCommentRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestCommentRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		commentRepository := NewCommentRepository()
+
+		user, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		todo, err := CreateTodo(ctx, dB, user)
+		So(err, ShouldBeNil)
+
+		Convey("can page through the comments of a todo oldest first", func() {
+
+			for i := 0; i < 3; i++ {
+				_, err := CreateComment(ctx, dB, todo, user)
+				So(err, ShouldBeNil)
+			}
+
+			comments, err := commentRepository.Comments(ctx, dB, todo.ID, 1, 2)
+			So(err, ShouldBeNil)
+
+			So(len(comments), ShouldEqual, 2)
+			So(comments[0].ID, ShouldBeLessThan, comments[1].ID)
+
+			comments, err = commentRepository.Comments(ctx, dB, todo.ID, 2, 2)
+			So(err, ShouldBeNil)
+
+			So(len(comments), ShouldEqual, 1)
+
+			count, err := commentRepository.NumberOfComments(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 3)
+		})
+
+		Convey("does not return soft deleted comments", func() {
+
+			comment, err := CreateComment(ctx, dB, todo, user)
+			So(err, ShouldBeNil)
+
+			timeNow := time.Now()
+			comment.DeletedAt = &timeNow
+
+			err = commentRepository.Save(ctx, dB, comment)
+			So(err, ShouldBeNil)
+
+			_, err = commentRepository.CommentByID(ctx, dB, comment.ID)
+			So(err, ShouldNotBeNil)
+
+			count, err := commentRepository.NumberOfComments(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 0)
+		})
+	}))
+}
@@ -7,8 +7,59 @@ import (
 	"github.com/ernestngugi/todo/internal/entities"
 )
 
-func CreateTodo(ctx context.Context, dB db.DB) (*entities.Todo, error) {
-	todo := entities.BuildTodo()
+func CreateTodo(ctx context.Context, dB db.DB, owner *entities.User) (*entities.Todo, error) {
+	todo := entities.BuildTodo(owner)
 	err := NewTodoRepository().Save(ctx, dB, todo)
 	return todo, err
 }
+
+func CreateUser(ctx context.Context, dB db.DB) (*entities.User, error) {
+	user := entities.BuildUser()
+	err := NewUserRepository().Save(ctx, dB, user)
+	return user, err
+}
+
+func CreateTag(ctx context.Context, dB db.DB, owner *entities.User) (*entities.Tag, error) {
+	tag := entities.BuildTag(owner)
+	err := NewTagRepository().Save(ctx, dB, tag)
+	return tag, err
+}
+
+func CreateTodoItem(ctx context.Context, dB db.DB, todo *entities.Todo) (*entities.TodoItem, error) {
+	todoItem := entities.BuildTodoItem(todo)
+	err := NewTodoItemRepository().Save(ctx, dB, todoItem)
+	return todoItem, err
+}
+
+func CreateProject(ctx context.Context, dB db.DB, owner *entities.User) (*entities.Project, error) {
+	project := entities.BuildProject(owner)
+	err := NewProjectRepository().Save(ctx, dB, project)
+	return project, err
+}
+
+func CreateWorkspace(ctx context.Context, dB db.DB, owner *entities.User) (*entities.Workspace, error) {
+
+	workspaceRepository := NewWorkspaceRepository()
+
+	workspace := entities.BuildWorkspace()
+
+	err := workspaceRepository.Save(ctx, dB, workspace)
+	if err != nil {
+		return workspace, err
+	}
+
+	workspace.Role = entities.WorkspaceRoleOwner
+
+	err = workspaceRepository.SaveMember(ctx, dB, &entities.WorkspaceMember{
+		WorkspaceID: workspace.ID,
+		UserID:      owner.ID,
+		Role:        entities.WorkspaceRoleOwner,
+	})
+	return workspace, err
+}
+
+func CreateComment(ctx context.Context, dB db.DB, todo *entities.Todo, author *entities.User) (*entities.Comment, error) {
+	comment := entities.BuildComment(todo, author)
+	err := NewCommentRepository().Save(ctx, dB, comment)
+	return comment, err
+}
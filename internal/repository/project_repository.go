@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	deleteProjectSQL      = "DELETE FROM projects WHERE id = $1"
+	detachTodosSQL        = "UPDATE todos SET project_id = NULL, updated_at = $1, version = version + 1 WHERE project_id = $2 RETURNING id"
+	getProjectByIDSQL     = selectProjectSQL + " WHERE projects.id = $1 GROUP BY projects.id"
+	getProjectsByOwnerSQL = selectProjectSQL + " WHERE projects.owner_id = $1 AND ($2 OR projects.archived_at IS NULL) GROUP BY projects.id ORDER BY projects.name, projects.id"
+	insertProjectSQL      = "INSERT INTO projects (owner_id, name, archived_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	selectProjectSQL      = "SELECT projects.id, projects.owner_id, projects.name, projects.archived_at, projects.created_at, projects.updated_at, COUNT(todos.id) FILTER (WHERE NOT todos.completed), COUNT(todos.id) FILTER (WHERE todos.completed) FROM projects LEFT JOIN todos ON todos.project_id = projects.id AND todos.deleted_at IS NULL"
+	updateProjectSQL      = "UPDATE projects SET name = $1, archived_at = $2, updated_at = $3 WHERE id = $4"
+)
+
+type (
+	ProjectRepository interface {
+		DeleteProject(ctx context.Context, operations db.SQLOperations, projectID int64) error
+		DetachTodos(ctx context.Context, operations db.SQLOperations, projectID int64) ([]int64, error)
+		ProjectByID(ctx context.Context, operations db.SQLOperations, projectID int64) (*entities.Project, error)
+		Projects(ctx context.Context, operations db.SQLOperations, ownerID int64, includeArchived bool) ([]*entities.Project, error)
+		Save(ctx context.Context, operations db.SQLOperations, project *entities.Project) error
+	}
+
+	projectRepository struct{}
+)
+
+func NewProjectRepository() ProjectRepository {
+	return &projectRepository{}
+}
+
+func (r *projectRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	project *entities.Project,
+) error {
+
+	project.Touch()
+
+	if project.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertProjectSQL,
+			project.OwnerID,
+			project.Name,
+			project.ArchivedAt,
+			project.CreatedAt,
+			project.UpdatedAt,
+		).Scan(&project.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateProjectSQL,
+		project.Name,
+		project.ArchivedAt,
+		project.UpdatedAt,
+		project.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *projectRepository) DeleteProject(
+	ctx context.Context,
+	operations db.SQLOperations,
+	projectID int64,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		deleteProjectSQL,
+		projectID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *projectRepository) ProjectByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	projectID int64,
+) (*entities.Project, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getProjectByIDSQL,
+		projectID,
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *projectRepository) Projects(
+	ctx context.Context,
+	operations db.SQLOperations,
+	ownerID int64,
+	includeArchived bool,
+) ([]*entities.Project, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getProjectsByOwnerSQL,
+		ownerID,
+		includeArchived,
+	)
+	if err != nil {
+		return []*entities.Project{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	projects := make([]*entities.Project, 0)
+
+	for rows.Next() {
+		project, err := r.scanRow(rows)
+		if err != nil {
+			return []*entities.Project{}, err
+		}
+
+		projects = append(projects, project)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.Project{}, apperror.NewDatabaseError(err)
+	}
+
+	return projects, nil
+}
+
+// DetachTodos takes the project's todos out of it, moving each to a new
+// version, and returns their ids.
+func (r *projectRepository) DetachTodos(
+	ctx context.Context,
+	operations db.SQLOperations,
+	projectID int64,
+) ([]int64, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		detachTodosSQL,
+		time.Now(),
+		projectID,
+	)
+	if err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	todoIDs := make([]int64, 0)
+
+	for rows.Next() {
+
+		var todoID int64
+
+		err := rows.Scan(&todoID)
+		if err != nil {
+			return []int64{}, apperror.NewDatabaseError(err)
+		}
+
+		todoIDs = append(todoIDs, todoID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	return todoIDs, nil
+}
+
+func (r *projectRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.Project, error) {
+
+	var project entities.Project
+
+	err := rowScanner.Scan(
+		&project.ID,
+		&project.OwnerID,
+		&project.Name,
+		&project.ArchivedAt,
+		&project.CreatedAt,
+		&project.UpdatedAt,
+		&project.OpenTodos,
+		&project.CompletedTodos,
+	)
+	if err != nil {
+		return &entities.Project{}, apperror.NewDatabaseError(err)
+	}
+
+	return &project, nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProjectRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestProjectRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		projectRepository := NewProjectRepository()
+		todoRepository := NewTodoRepository()
+
+		user, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		project, err := CreateProject(ctx, dB, user)
+		So(err, ShouldBeNil)
+
+		Convey("can count open and completed todos of a project", func() {
+
+			for _, completed := range []bool{true, false, false} {
+
+				todo, err := CreateTodo(ctx, dB, user)
+				So(err, ShouldBeNil)
+
+				todo.ProjectID = &project.ID
+				todo.Completed = completed
+
+				err = todoRepository.Save(ctx, dB, todo)
+				So(err, ShouldBeNil)
+			}
+
+			foundProject, err := projectRepository.ProjectByID(ctx, dB, project.ID)
+			So(err, ShouldBeNil)
+
+			So(foundProject.OpenTodos, ShouldEqual, 2)
+			So(foundProject.CompletedTodos, ShouldEqual, 1)
+		})
+
+		Convey("can list projects without archived ones", func() {
+
+			archivedProject, err := CreateProject(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			timeNow := time.Now()
+			archivedProject.ArchivedAt = &timeNow
+
+			err = projectRepository.Save(ctx, dB, archivedProject)
+			So(err, ShouldBeNil)
+
+			projects, err := projectRepository.Projects(ctx, dB, user.ID, false)
+			So(err, ShouldBeNil)
+
+			So(len(projects), ShouldEqual, 1)
+			So(projects[0].ID, ShouldEqual, project.ID)
+
+			projects, err = projectRepository.Projects(ctx, dB, user.ID, true)
+			So(err, ShouldBeNil)
+
+			So(len(projects), ShouldEqual, 2)
+		})
+
+		Convey("hides todos of archived projects from default listings", func() {
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			todo.ProjectID = &project.ID
+
+			err = todoRepository.Save(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			timeNow := time.Now()
+			project.ArchivedAt = &timeNow
+
+			err = projectRepository.Save(ctx, dB, project)
+			So(err, ShouldBeNil)
+
+			count, err := todoRepository.NumberOfTodos(ctx, dB, &forms.Filter{OwnerID: user.ID})
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 1)
+
+			count, err = todoRepository.NumberOfTodos(ctx, dB, &forms.Filter{OwnerID: user.ID, IncludeArchived: true})
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 2)
+
+			count, err = todoRepository.NumberOfTodos(ctx, dB, &forms.Filter{OwnerID: user.ID, ProjectID: project.ID})
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 1)
+		})
+	}))
+}
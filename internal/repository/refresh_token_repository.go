@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	getRefreshTokenByHashSQL = selectRefreshTokenSQL + " WHERE token_hash = $1"
+	insertRefreshTokenSQL    = "INSERT INTO refresh_tokens (user_id, token_hash, expires_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+	revokeRefreshTokenSQL    = "UPDATE refresh_tokens SET revoked_at = $1, updated_at = $1 WHERE id = $2 AND revoked_at IS NULL"
+	selectRefreshTokenSQL    = "SELECT id, user_id, token_hash, expires_at, revoked_at, created_at, updated_at FROM refresh_tokens"
+	updateRefreshTokenSQL    = "UPDATE refresh_tokens SET revoked_at = $1, updated_at = $2 WHERE id = $3"
+)
+
+type (
+	RefreshTokenRepository interface {
+		RefreshTokenByHash(ctx context.Context, operations db.SQLOperations, tokenHash string) (*entities.RefreshToken, error)
+		Revoke(ctx context.Context, operations db.SQLOperations, refreshToken *entities.RefreshToken) (bool, error)
+		Save(ctx context.Context, operations db.SQLOperations, refreshToken *entities.RefreshToken) error
+	}
+
+	refreshTokenRepository struct{}
+)
+
+func NewRefreshTokenRepository() RefreshTokenRepository {
+	return &refreshTokenRepository{}
+}
+
+func (r *refreshTokenRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	refreshToken *entities.RefreshToken,
+) error {
+
+	refreshToken.Touch()
+
+	if refreshToken.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertRefreshTokenSQL,
+			refreshToken.UserID,
+			refreshToken.TokenHash,
+			refreshToken.ExpiresAt,
+			refreshToken.CreatedAt,
+			refreshToken.UpdatedAt,
+		).Scan(&refreshToken.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateRefreshTokenSQL,
+		refreshToken.RevokedAt,
+		refreshToken.UpdatedAt,
+		refreshToken.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *refreshTokenRepository) RefreshTokenByHash(
+	ctx context.Context,
+	operations db.SQLOperations,
+	tokenHash string,
+) (*entities.RefreshToken, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getRefreshTokenByHashSQL,
+		tokenHash,
+	)
+
+	return r.scanRow(row)
+}
+
+// Revoke reports false when the token had already been revoked, so only one
+// of several requests presenting the same token can use it.
+func (r *refreshTokenRepository) Revoke(
+	ctx context.Context,
+	operations db.SQLOperations,
+	refreshToken *entities.RefreshToken,
+) (bool, error) {
+
+	timeNow := time.Now()
+
+	result, err := operations.ExecContext(
+		ctx,
+		revokeRefreshTokenSQL,
+		timeNow,
+		refreshToken.ID,
+	)
+	if err != nil {
+		return false, apperror.NewDatabaseError(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, apperror.NewDatabaseError(err)
+	}
+
+	if rowsAffected != 1 {
+		return false, nil
+	}
+
+	refreshToken.RevokedAt = &timeNow
+	refreshToken.UpdatedAt = timeNow
+
+	return true, nil
+}
+
+func (r *refreshTokenRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.RefreshToken, error) {
+
+	var refreshToken entities.RefreshToken
+
+	err := rowScanner.Scan(
+		&refreshToken.ID,
+		&refreshToken.UserID,
+		&refreshToken.TokenHash,
+		&refreshToken.ExpiresAt,
+		&refreshToken.RevokedAt,
+		&refreshToken.CreatedAt,
+		&refreshToken.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.RefreshToken{}, apperror.NewDatabaseError(err)
+	}
+
+	return &refreshToken, nil
+}
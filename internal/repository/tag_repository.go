@@ -0,0 +1,367 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/lib/pq"
+)
+
+const (
+	attachTagsSQL      = "INSERT INTO todo_tags (todo_id, tag_id) SELECT $1, UNNEST($2::BIGINT[]) ON CONFLICT DO NOTHING"
+	deleteTagSQL       = "DELETE FROM tags WHERE id = $1"
+	detachTagsSQL      = "DELETE FROM todo_tags WHERE todo_id = $1 AND tag_id = ANY($2)"
+	getTagByIDSQL      = selectTagSQL + " WHERE id = $1"
+	getTagByNameSQL    = selectTagSQL + " WHERE owner_id = $1 AND name = $2"
+	getTagsByIDsSQL    = selectTagSQL + " WHERE owner_id = $1 AND id = ANY($2) ORDER BY name"
+	getTagsByOwnerSQL  = selectTagSQL + " WHERE owner_id = $1 ORDER BY name"
+	getTagsForTodosSQL = "SELECT todo_tags.todo_id, tags.id, tags.owner_id, tags.name, tags.created_at, tags.updated_at FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE todo_tags.todo_id = ANY($1) ORDER BY tags.name"
+	getTodoIDsByTagSQL = "SELECT todo_id FROM todo_tags WHERE tag_id = $1"
+	insertTagSQL       = "INSERT INTO tags (owner_id, name, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	selectTagSQL       = "SELECT id, owner_id, name, created_at, updated_at FROM tags"
+	updateTagSQL       = "UPDATE tags SET name = $1, updated_at = $2 WHERE id = $3"
+)
+
+type (
+	TagRepository interface {
+		AttachTags(ctx context.Context, operations db.SQLOperations, todoID int64, tagIDs []int64) error
+		DeleteTag(ctx context.Context, operations db.SQLOperations, tagID int64) error
+		DetachTags(ctx context.Context, operations db.SQLOperations, todoID int64, tagIDs []int64) error
+		Save(ctx context.Context, operations db.SQLOperations, tag *entities.Tag) error
+		TagByID(ctx context.Context, operations db.SQLOperations, tagID int64) (*entities.Tag, error)
+		TagByName(ctx context.Context, operations db.SQLOperations, ownerID int64, name string) (*entities.Tag, error)
+		Tags(ctx context.Context, operations db.SQLOperations, ownerID int64) ([]*entities.Tag, error)
+		TagsByIDs(ctx context.Context, operations db.SQLOperations, ownerID int64, tagIDs []int64) ([]*entities.Tag, error)
+		TagsForTodos(ctx context.Context, operations db.SQLOperations, todoIDs []int64) (map[int64][]*entities.Tag, error)
+		TodoIDsByTag(ctx context.Context, operations db.SQLOperations, tagID int64) ([]int64, error)
+	}
+
+	tagRepository struct{}
+)
+
+func NewTagRepository() TagRepository {
+	return &tagRepository{}
+}
+
+func (r *tagRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	tag *entities.Tag,
+) error {
+
+	tag.Touch()
+
+	tag.Name = strings.ToLower(strings.TrimSpace(tag.Name))
+
+	if tag.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertTagSQL,
+			tag.OwnerID,
+			tag.Name,
+			tag.CreatedAt,
+			tag.UpdatedAt,
+		).Scan(&tag.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateTagSQL,
+		tag.Name,
+		tag.UpdatedAt,
+		tag.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *tagRepository) DeleteTag(
+	ctx context.Context,
+	operations db.SQLOperations,
+	tagID int64,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		deleteTagSQL,
+		tagID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *tagRepository) AttachTags(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+	tagIDs []int64,
+) error {
+
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		attachTagsSQL,
+		todoID,
+		pq.Array(tagIDs),
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *tagRepository) DetachTags(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+	tagIDs []int64,
+) error {
+
+	if len(tagIDs) == 0 {
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		detachTagsSQL,
+		todoID,
+		pq.Array(tagIDs),
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *tagRepository) TagByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	tagID int64,
+) (*entities.Tag, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getTagByIDSQL,
+		tagID,
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *tagRepository) TagByName(
+	ctx context.Context,
+	operations db.SQLOperations,
+	ownerID int64,
+	name string,
+) (*entities.Tag, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getTagByNameSQL,
+		ownerID,
+		strings.ToLower(strings.TrimSpace(name)),
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *tagRepository) Tags(
+	ctx context.Context,
+	operations db.SQLOperations,
+	ownerID int64,
+) ([]*entities.Tag, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTagsByOwnerSQL,
+		ownerID,
+	)
+	if err != nil {
+		return []*entities.Tag{}, apperror.NewDatabaseError(err)
+	}
+
+	return r.scanRows(rows)
+}
+
+func (r *tagRepository) TagsByIDs(
+	ctx context.Context,
+	operations db.SQLOperations,
+	ownerID int64,
+	tagIDs []int64,
+) ([]*entities.Tag, error) {
+
+	if len(tagIDs) == 0 {
+		return []*entities.Tag{}, nil
+	}
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTagsByIDsSQL,
+		ownerID,
+		pq.Array(tagIDs),
+	)
+	if err != nil {
+		return []*entities.Tag{}, apperror.NewDatabaseError(err)
+	}
+
+	return r.scanRows(rows)
+}
+
+// TagsForTodos loads the tags of many todos in a single query, keyed by todo
+// id. Every requested todo has an entry, even when it has no tags.
+func (r *tagRepository) TagsForTodos(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoIDs []int64,
+) (map[int64][]*entities.Tag, error) {
+
+	tagsByTodo := make(map[int64][]*entities.Tag, len(todoIDs))
+
+	if len(todoIDs) == 0 {
+		return tagsByTodo, nil
+	}
+
+	for _, todoID := range todoIDs {
+		tagsByTodo[todoID] = make([]*entities.Tag, 0)
+	}
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTagsForTodosSQL,
+		pq.Array(todoIDs),
+	)
+	if err != nil {
+		return map[int64][]*entities.Tag{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var todoID int64
+		var tag entities.Tag
+
+		err := rows.Scan(
+			&todoID,
+			&tag.ID,
+			&tag.OwnerID,
+			&tag.Name,
+			&tag.CreatedAt,
+			&tag.UpdatedAt,
+		)
+		if err != nil {
+			return map[int64][]*entities.Tag{}, apperror.NewDatabaseError(err)
+		}
+
+		tagsByTodo[todoID] = append(tagsByTodo[todoID], &tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return map[int64][]*entities.Tag{}, apperror.NewDatabaseError(err)
+	}
+
+	return tagsByTodo, nil
+}
+
+func (r *tagRepository) TodoIDsByTag(
+	ctx context.Context,
+	operations db.SQLOperations,
+	tagID int64,
+) ([]int64, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTodoIDsByTagSQL,
+		tagID,
+	)
+	if err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	todoIDs := make([]int64, 0)
+
+	for rows.Next() {
+
+		var todoID int64
+
+		err := rows.Scan(&todoID)
+		if err != nil {
+			return []int64{}, apperror.NewDatabaseError(err)
+		}
+
+		todoIDs = append(todoIDs, todoID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	return todoIDs, nil
+}
+
+func (r *tagRepository) scanRows(
+	rows *sql.Rows,
+) ([]*entities.Tag, error) {
+
+	defer rows.Close()
+
+	tags := make([]*entities.Tag, 0)
+
+	for rows.Next() {
+		tag, err := r.scanRow(rows)
+		if err != nil {
+			return []*entities.Tag{}, err
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.Tag{}, apperror.NewDatabaseError(err)
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.Tag, error) {
+
+	var tag entities.Tag
+
+	err := rowScanner.Scan(
+		&tag.ID,
+		&tag.OwnerID,
+		&tag.Name,
+		&tag.CreatedAt,
+		&tag.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.Tag{}, apperror.NewDatabaseError(err)
+	}
+
+	return &tag, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTagRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestTagRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		tagRepository := NewTagRepository()
+		todoRepository := NewTodoRepository()
+
+		user, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		Convey("can save a tag with a normalized name", func() {
+
+			tag := &entities.Tag{
+				OwnerID: user.ID,
+				Name:    " Work ",
+			}
+
+			err := tagRepository.Save(ctx, dB, tag)
+			So(err, ShouldBeNil)
+
+			So(tag.ID, ShouldNotBeZeroValue)
+
+			foundTag, err := tagRepository.TagByName(ctx, dB, user.ID, "WORK")
+			So(err, ShouldBeNil)
+
+			So(foundTag.ID, ShouldEqual, tag.ID)
+			So(foundTag.Name, ShouldEqual, "work")
+		})
+
+		Convey("can attach and detach tags", func() {
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			work, err := CreateTag(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			home, err := CreateTag(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = tagRepository.AttachTags(ctx, dB, todo.ID, []int64{work.ID, home.ID, work.ID})
+			So(err, ShouldBeNil)
+
+			foundTodo, err := todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(len(foundTodo.Tags), ShouldEqual, 2)
+
+			err = tagRepository.DetachTags(ctx, dB, todo.ID, []int64{work.ID})
+			So(err, ShouldBeNil)
+
+			foundTodo, err = todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(len(foundTodo.Tags), ShouldEqual, 1)
+			So(foundTodo.Tags[0].ID, ShouldEqual, home.ID)
+		})
+
+		Convey("can load tags for many todos at once", func() {
+
+			tagged, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			untagged, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			tag, err := CreateTag(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = tagRepository.AttachTags(ctx, dB, tagged.ID, []int64{tag.ID})
+			So(err, ShouldBeNil)
+
+			tagsByTodo, err := tagRepository.TagsForTodos(ctx, dB, []int64{tagged.ID, untagged.ID})
+			So(err, ShouldBeNil)
+
+			So(len(tagsByTodo[tagged.ID]), ShouldEqual, 1)
+			So(tagsByTodo[untagged.ID], ShouldNotBeNil)
+			So(len(tagsByTodo[untagged.ID]), ShouldEqual, 0)
+		})
+
+		Convey("can filter todos by any or all tags", func() {
+
+			work := &entities.Tag{OwnerID: user.ID, Name: "work"}
+			err := tagRepository.Save(ctx, dB, work)
+			So(err, ShouldBeNil)
+
+			urgent := &entities.Tag{OwnerID: user.ID, Name: "urgent"}
+			err = tagRepository.Save(ctx, dB, urgent)
+			So(err, ShouldBeNil)
+
+			workOnly, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = tagRepository.AttachTags(ctx, dB, workOnly.ID, []int64{work.ID})
+			So(err, ShouldBeNil)
+
+			both, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = tagRepository.AttachTags(ctx, dB, both.ID, []int64{work.ID, urgent.ID})
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			filter := &forms.Filter{
+				OwnerID:  user.ID,
+				TagMatch: forms.TagMatchAny,
+				Tags:     []string{"work", "urgent"},
+			}
+
+			todos, err := todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 2)
+
+			filter.TagMatch = forms.TagMatchAll
+
+			todos, err = todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 1)
+			So(todos[0].ID, ShouldEqual, both.ID)
+
+			count, err := todoRepository.NumberOfTodos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("only matches the caller's own tags by name", func() {
+
+			member, err := CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			workspace, err := CreateWorkspace(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = NewWorkspaceRepository().SaveMember(ctx, dB, &entities.WorkspaceMember{
+				WorkspaceID: workspace.ID,
+				UserID:      member.ID,
+				Role:        entities.WorkspaceRoleEditor,
+			})
+			So(err, ShouldBeNil)
+
+			work := &entities.Tag{OwnerID: user.ID, Name: "work"}
+			err = tagRepository.Save(ctx, dB, work)
+			So(err, ShouldBeNil)
+
+			memberWork := &entities.Tag{OwnerID: member.ID, Name: "work"}
+			err = tagRepository.Save(ctx, dB, memberWork)
+			So(err, ShouldBeNil)
+
+			memberUrgent := &entities.Tag{OwnerID: member.ID, Name: "urgent"}
+			err = tagRepository.Save(ctx, dB, memberUrgent)
+			So(err, ShouldBeNil)
+
+			todo := entities.BuildTodo(user)
+			todo.WorkspaceID = &workspace.ID
+
+			err = todoRepository.Save(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			err = tagRepository.AttachTags(ctx, dB, todo.ID, []int64{work.ID, memberWork.ID})
+			So(err, ShouldBeNil)
+
+			filter := &forms.Filter{
+				OwnerID:     user.ID,
+				TagMatch:    forms.TagMatchAll,
+				Tags:        []string{"work", "urgent"},
+				WorkspaceID: workspace.ID,
+			}
+
+			todos, err := todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 0)
+
+			err = tagRepository.AttachTags(ctx, dB, todo.ID, []int64{memberUrgent.ID})
+			So(err, ShouldBeNil)
+
+			todos, err = todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 0)
+
+			filter.Tags = []string{"work"}
+
+			todos, err = todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 1)
+			So(todos[0].ID, ShouldEqual, todo.ID)
+		})
+	}))
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	getTodoActivitiesSQL  = "SELECT id, todo_id, actor_id, action, assignee_id, created_at FROM todo_activities WHERE todo_id = $1 ORDER BY created_at, id"
+	insertTodoActivitySQL = "INSERT INTO todo_activities (todo_id, actor_id, action, assignee_id, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id"
+)
+
+type (
+	TodoActivityRepository interface {
+		Save(ctx context.Context, operations db.SQLOperations, activity *entities.TodoActivity) error
+		TodoActivities(ctx context.Context, operations db.SQLOperations, todoID int64) ([]*entities.TodoActivity, error)
+	}
+
+	todoActivityRepository struct{}
+)
+
+func NewTodoActivityRepository() TodoActivityRepository {
+	return &todoActivityRepository{}
+}
+
+// Save records a new activity; activities are never changed once written.
+func (r *todoActivityRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	activity *entities.TodoActivity,
+) error {
+
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	err := operations.QueryRowContext(
+		ctx,
+		insertTodoActivitySQL,
+		activity.TodoID,
+		activity.ActorID,
+		activity.Action,
+		activity.AssigneeID,
+		activity.CreatedAt,
+	).Scan(&activity.ID)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *todoActivityRepository) TodoActivities(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+) ([]*entities.TodoActivity, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTodoActivitiesSQL,
+		todoID,
+	)
+	if err != nil {
+		return []*entities.TodoActivity{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	activities := make([]*entities.TodoActivity, 0)
+
+	for rows.Next() {
+
+		var activity entities.TodoActivity
+
+		err := rows.Scan(
+			&activity.ID,
+			&activity.TodoID,
+			&activity.ActorID,
+			&activity.Action,
+			&activity.AssigneeID,
+			&activity.CreatedAt,
+		)
+		if err != nil {
+			return []*entities.TodoActivity{}, apperror.NewDatabaseError(err)
+		}
+
+		activities = append(activities, &activity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.TodoActivity{}, apperror.NewDatabaseError(err)
+	}
+
+	return activities, nil
+}
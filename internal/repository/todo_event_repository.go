@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	getTodoEventsSQL   = "SELECT id, todo_id, actor_id, action, request_id, user_agent, changes, created_at FROM todo_events WHERE todo_id = $1 ORDER BY created_at, id"
+	insertTodoEventSQL = "INSERT INTO todo_events (todo_id, actor_id, action, request_id, user_agent, changes, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id"
+)
+
+type (
+	TodoEventRepository interface {
+		Save(ctx context.Context, operations db.SQLOperations, event *entities.TodoEvent) error
+		TodoEvents(ctx context.Context, operations db.SQLOperations, todoID int64) ([]*entities.TodoEvent, error)
+	}
+
+	todoEventRepository struct{}
+)
+
+func NewTodoEventRepository() TodoEventRepository {
+	return &todoEventRepository{}
+}
+
+// Save records a new event; events are never changed once written.
+func (r *todoEventRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	event *entities.TodoEvent,
+) error {
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	if event.Changes == nil {
+		event.Changes = make(map[string]*entities.FieldChange)
+	}
+
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return err
+	}
+
+	err = operations.QueryRowContext(
+		ctx,
+		insertTodoEventSQL,
+		event.TodoID,
+		event.ActorID,
+		event.Action,
+		event.RequestID,
+		event.UserAgent,
+		changes,
+		event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *todoEventRepository) TodoEvents(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+) ([]*entities.TodoEvent, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTodoEventsSQL,
+		todoID,
+	)
+	if err != nil {
+		return []*entities.TodoEvent{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	events := make([]*entities.TodoEvent, 0)
+
+	for rows.Next() {
+
+		var event entities.TodoEvent
+		var changes []byte
+
+		err := rows.Scan(
+			&event.ID,
+			&event.TodoID,
+			&event.ActorID,
+			&event.Action,
+			&event.RequestID,
+			&event.UserAgent,
+			&changes,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return []*entities.TodoEvent{}, apperror.NewDatabaseError(err)
+		}
+
+		err = json.Unmarshal(changes, &event.Changes)
+		if err != nil {
+			return []*entities.TodoEvent{}, err
+		}
+
+		events = append(events, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.TodoEvent{}, apperror.NewDatabaseError(err)
+	}
+
+	return events, nil
+}
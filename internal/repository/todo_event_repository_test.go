@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTodoEventRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestTodoEventRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		todoEventRepository := NewTodoEventRepository()
+
+		user, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		todo, err := CreateTodo(ctx, dB, user)
+		So(err, ShouldBeNil)
+
+		Convey("can save and list the events of a todo with their changes", func() {
+
+			event := &entities.TodoEvent{
+				TodoID:    todo.ID,
+				ActorID:   &user.ID,
+				Action:    entities.TodoEventUpdated,
+				RequestID: "request-id",
+				Changes: map[string]*entities.FieldChange{
+					"title": {From: "before", To: "after"},
+				},
+			}
+
+			err := todoEventRepository.Save(ctx, dB, event)
+			So(err, ShouldBeNil)
+
+			So(event.ID, ShouldBeGreaterThan, 0)
+
+			err = todoEventRepository.Save(ctx, dB, &entities.TodoEvent{TodoID: todo.ID, Action: entities.TodoEventCompleted})
+			So(err, ShouldBeNil)
+
+			events, err := todoEventRepository.TodoEvents(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(len(events), ShouldEqual, 2)
+			So(events[0].RequestID, ShouldEqual, "request-id")
+			So(events[0].Changes["title"].To, ShouldEqual, "after")
+			So(events[1].ActorID, ShouldBeNil)
+			So(len(events[1].Changes), ShouldEqual, 0)
+		})
+	}))
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/lib/pq"
+)
+
+const (
+	completeOpenTodoItemsSQL = "UPDATE todo_items SET completed = TRUE, completed_at = $1, updated_at = $1 WHERE todo_id = $2 AND completed = FALSE"
+	countTodoItemsSQL        = "SELECT todo_id, COUNT(id), COUNT(id) FILTER (WHERE completed) FROM todo_items WHERE todo_id = ANY($1) GROUP BY todo_id"
+	deleteTodoItemSQL        = "DELETE FROM todo_items WHERE id = $1"
+	getTodoItemByIDSQL       = selectTodoItemSQL + " WHERE id = $1"
+	getTodoItemsSQL          = selectTodoItemSQL + " WHERE todo_id = $1 ORDER BY position, id"
+	insertTodoItemSQL        = "INSERT INTO todo_items (todo_id, title, completed, completed_at, position, created_at, updated_at) SELECT $1, $2, $3, $4, COALESCE(MAX(position), 0) + 1, $5, $6 FROM todo_items WHERE todo_id = $1 RETURNING id, position"
+	reorderTodoItemsSQL      = "UPDATE todo_items SET position = ordering.position, updated_at = $3 FROM UNNEST($2::BIGINT[]) WITH ORDINALITY AS ordering(id, position) WHERE todo_items.todo_id = $1 AND todo_items.id = ordering.id"
+	selectTodoItemSQL        = "SELECT id, todo_id, title, completed, completed_at, position, created_at, updated_at FROM todo_items"
+	updateTodoItemSQL        = "UPDATE todo_items SET title = $1, completed = $2, completed_at = $3, updated_at = $4 WHERE id = $5"
+)
+
+type (
+	TodoItemRepository interface {
+		CompleteOpenItems(ctx context.Context, operations db.SQLOperations, todoID int64, completedAt time.Time) error
+		DeleteTodoItem(ctx context.Context, operations db.SQLOperations, todoItemID int64) error
+		ItemCountsForTodos(ctx context.Context, operations db.SQLOperations, todoIDs []int64) (map[int64]entities.TodoItemCounts, error)
+		ReorderTodoItems(ctx context.Context, operations db.SQLOperations, todoID int64, todoItemIDs []int64) error
+		Save(ctx context.Context, operations db.SQLOperations, todoItem *entities.TodoItem) error
+		TodoItemByID(ctx context.Context, operations db.SQLOperations, todoItemID int64) (*entities.TodoItem, error)
+		TodoItems(ctx context.Context, operations db.SQLOperations, todoID int64) ([]*entities.TodoItem, error)
+	}
+
+	todoItemRepository struct{}
+)
+
+func NewTodoItemRepository() TodoItemRepository {
+	return &todoItemRepository{}
+}
+
+// Save appends new items to the end of the checklist; positions of existing
+// items only change through ReorderTodoItems.
+func (r *todoItemRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoItem *entities.TodoItem,
+) error {
+
+	todoItem.Touch()
+
+	if todoItem.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertTodoItemSQL,
+			todoItem.TodoID,
+			todoItem.Title,
+			todoItem.Completed,
+			todoItem.CompletedAt,
+			todoItem.CreatedAt,
+			todoItem.UpdatedAt,
+		).Scan(&todoItem.ID, &todoItem.Position)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateTodoItemSQL,
+		todoItem.Title,
+		todoItem.Completed,
+		todoItem.CompletedAt,
+		todoItem.UpdatedAt,
+		todoItem.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *todoItemRepository) CompleteOpenItems(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+	completedAt time.Time,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		completeOpenTodoItemsSQL,
+		completedAt,
+		todoID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *todoItemRepository) DeleteTodoItem(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoItemID int64,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		deleteTodoItemSQL,
+		todoItemID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+// ItemCountsForTodos counts the items of many todos in a single query. Todos
+// without items are left out of the result.
+func (r *todoItemRepository) ItemCountsForTodos(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoIDs []int64,
+) (map[int64]entities.TodoItemCounts, error) {
+
+	itemCounts := make(map[int64]entities.TodoItemCounts, len(todoIDs))
+
+	if len(todoIDs) == 0 {
+		return itemCounts, nil
+	}
+
+	rows, err := operations.QueryContext(
+		ctx,
+		countTodoItemsSQL,
+		pq.Array(todoIDs),
+	)
+	if err != nil {
+		return map[int64]entities.TodoItemCounts{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var todoID int64
+		var counts entities.TodoItemCounts
+
+		err := rows.Scan(&todoID, &counts.Total, &counts.Completed)
+		if err != nil {
+			return map[int64]entities.TodoItemCounts{}, apperror.NewDatabaseError(err)
+		}
+
+		itemCounts[todoID] = counts
+	}
+
+	if err := rows.Err(); err != nil {
+		return map[int64]entities.TodoItemCounts{}, apperror.NewDatabaseError(err)
+	}
+
+	return itemCounts, nil
+}
+
+// ReorderTodoItems assigns positions following the order of todoItemIDs in a
+// single statement.
+func (r *todoItemRepository) ReorderTodoItems(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+	todoItemIDs []int64,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		reorderTodoItemsSQL,
+		todoID,
+		pq.Array(todoItemIDs),
+		time.Now(),
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *todoItemRepository) TodoItemByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoItemID int64,
+) (*entities.TodoItem, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getTodoItemByIDSQL,
+		todoItemID,
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *todoItemRepository) TodoItems(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+) ([]*entities.TodoItem, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTodoItemsSQL,
+		todoID,
+	)
+	if err != nil {
+		return []*entities.TodoItem{}, apperror.NewDatabaseError(err)
+	}
+
+	return r.scanRows(rows)
+}
+
+func (r *todoItemRepository) scanRows(
+	rows *sql.Rows,
+) ([]*entities.TodoItem, error) {
+
+	defer rows.Close()
+
+	todoItems := make([]*entities.TodoItem, 0)
+
+	for rows.Next() {
+		todoItem, err := r.scanRow(rows)
+		if err != nil {
+			return []*entities.TodoItem{}, err
+		}
+
+		todoItems = append(todoItems, todoItem)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.TodoItem{}, apperror.NewDatabaseError(err)
+	}
+
+	return todoItems, nil
+}
+
+func (r *todoItemRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.TodoItem, error) {
+
+	var todoItem entities.TodoItem
+
+	err := rowScanner.Scan(
+		&todoItem.ID,
+		&todoItem.TodoID,
+		&todoItem.Title,
+		&todoItem.Completed,
+		&todoItem.CompletedAt,
+		&todoItem.Position,
+		&todoItem.CreatedAt,
+		&todoItem.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.TodoItem{}, apperror.NewDatabaseError(err)
+	}
+
+	return &todoItem, nil
+}
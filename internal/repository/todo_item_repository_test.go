@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestTodoItemRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestTodoItemRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		todoItemRepository := NewTodoItemRepository()
+		todoRepository := NewTodoRepository()
+
+		user, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		todo, err := CreateTodo(ctx, dB, user)
+		So(err, ShouldBeNil)
+
+		Convey("appends new items to the end of the checklist", func() {
+
+			first, err := CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			second, err := CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			So(first.Position, ShouldEqual, 1)
+			So(second.Position, ShouldEqual, 2)
+		})
+
+		Convey("can reorder items", func() {
+
+			first, err := CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			second, err := CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			err = todoItemRepository.ReorderTodoItems(ctx, dB, todo.ID, []int64{second.ID, first.ID})
+			So(err, ShouldBeNil)
+
+			todoItems, err := todoItemRepository.TodoItems(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(len(todoItems), ShouldEqual, 2)
+			So(todoItems[0].ID, ShouldEqual, second.ID)
+			So(todoItems[1].ID, ShouldEqual, first.ID)
+		})
+
+		Convey("includes item counts in todos", func() {
+
+			todoItem, err := CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			timeNow := time.Now()
+			todoItem.Completed = true
+			todoItem.CompletedAt = &timeNow
+
+			err = todoItemRepository.Save(ctx, dB, todoItem)
+			So(err, ShouldBeNil)
+
+			foundTodo, err := todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.ItemsTotal, ShouldEqual, 2)
+			So(foundTodo.ItemsCompleted, ShouldEqual, 1)
+			So(foundTodo.Progress, ShouldEqual, "1/2 done")
+		})
+
+		Convey("can complete all open items", func() {
+
+			_, err := CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodoItem(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			err = todoItemRepository.CompleteOpenItems(ctx, dB, todo.ID, time.Now())
+			So(err, ShouldBeNil)
+
+			itemCounts, err := todoItemRepository.ItemCountsForTodos(ctx, dB, []int64{todo.ID})
+			So(err, ShouldBeNil)
+
+			So(itemCounts[todo.ID].Completed, ShouldEqual, 2)
+			So(itemCounts[todo.ID].Total, ShouldEqual, 2)
+		})
+	}))
+}
@@ -2,29 +2,62 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/ernestngugi/todo/internal/apperror"
 	"github.com/ernestngugi/todo/internal/db"
 	"github.com/ernestngugi/todo/internal/entities"
 	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/lib/pq"
 )
 
 const (
-	countTodoSQL   = "SELECT COUNT(id) FROM todos"
-	deleteTodoSQL  = "DELETE FROM todos WHERE id = $1"
-	getTodoByIDSQL = selectTodoSQL + " WHERE id = $1"
-	insertTodoSQL  = "INSERT INTO todos (title, description, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id"
-	selectTodoSQL  = "SELECT id, title, description, completed, completed_at, created_at, updated_at FROM todos"
-	updateTodoSQL  = "UPDATE todos SET title = $1, description = $2, completed = $3, completed_at = $4, updated_at = $5 WHERE id = $6"
+	bumpTodoVersionSQL          = "UPDATE todos SET updated_at = $1, version = version + 1 WHERE id = $2 RETURNING version"
+	countTodoSQL                = "SELECT COUNT(id) FROM todos"
+	deleteTodoSQL               = "UPDATE todos SET deleted_at = $1, version = version + 1 WHERE id = $2 AND version = $3 AND deleted_at IS NULL"
+	getDeletedTodoByIDSQL       = selectTodoSQL + " WHERE id = $1 AND deleted_at IS NOT NULL"
+	getPurgeableTodoIDsSQL      = "SELECT id FROM todos WHERE deleted_at < $1 ORDER BY deleted_at, id LIMIT $2 FOR UPDATE SKIP LOCKED"
+	getTodoByIDSQL              = selectTodoSQL + " WHERE id = $1 AND deleted_at IS NULL"
+	getTodosByIDsSQL            = selectTodoSQL + " WHERE id = ANY($1) AND deleted_at IS NULL ORDER BY id"
+	selectMemberWorkspaceIDsSQL = "SELECT workspace_id FROM workspace_members WHERE user_id = %v"
+	selectTaggedTodoIDsSQL      = "SELECT todo_tags.todo_id FROM todo_tags JOIN tags ON tags.id = todo_tags.tag_id WHERE tags.owner_id = %v AND tags.name = ANY(%v)"
+	insertTodoSQL               = "INSERT INTO todos (owner_id, project_id, workspace_id, assignee_id, title, description, status, completed, completed_at, due_at, priority, recurrence_rule, series_id, series_start_at, occurrence, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17) RETURNING id, version"
+	lockTodoSQL                 = "SELECT id FROM todos WHERE id = $1 FOR UPDATE"
+	purgeTodosSQL               = "DELETE FROM todos WHERE id = ANY($1) AND deleted_at IS NOT NULL RETURNING id"
+	restoreTodoSQL              = "UPDATE todos SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND version = $2 AND deleted_at IS NOT NULL"
+	selectTodoSQL               = "SELECT id, owner_id, project_id, workspace_id, assignee_id, title, description, status, completed, completed_at, due_at, priority, recurrence_rule, series_id, series_start_at, occurrence, deleted_at, version, created_at, updated_at FROM todos"
+	updateTodoSQL               = "UPDATE todos SET project_id = $1, assignee_id = $2, title = $3, description = $4, status = $5, completed = $6, completed_at = $7, due_at = $8, priority = $9, recurrence_rule = $10, series_id = $11, series_start_at = $12, occurrence = $13, updated_at = $14, version = version + 1 WHERE id = $15 AND version = $16"
 )
 
+var todoSortColumns = map[string]string{
+	forms.SortFieldCompletedAt: "COALESCE(completed_at, 'infinity'::timestamptz)",
+	forms.SortFieldCreatedAt:   "created_at",
+	forms.SortFieldDueAt:       "COALESCE(due_at, 'infinity'::timestamptz)",
+	forms.SortFieldPriority:    "priority",
+	forms.SortFieldTitle:       "title",
+	forms.SortFieldUpdatedAt:   "updated_at",
+}
+
 type (
 	TodoRepository interface {
-		DeleteTodo(ctx context.Context, operations db.SQLOperations, todoID int64) error
+		BumpVersion(ctx context.Context, operations db.SQLOperations, todo *entities.Todo) error
+		DeleteTodo(ctx context.Context, operations db.SQLOperations, todo *entities.Todo) error
+		DeletedTodoByID(ctx context.Context, operations db.SQLOperations, todoID int64) (*entities.Todo, error)
+		LockTodo(ctx context.Context, operations db.SQLOperations, todoID int64) error
 		NumberOfTodos(ctx context.Context, operations db.SQLOperations, filter *forms.Filter) (int, error)
+		PurgeableTodoIDs(ctx context.Context, operations db.SQLOperations, deletedBefore time.Time, limit int) ([]int64, error)
+		PurgeTodos(ctx context.Context, operations db.SQLOperations, todoIDs []int64) ([]int64, error)
+		RestoreTodo(ctx context.Context, operations db.SQLOperations, todo *entities.Todo) error
 		Save(ctx context.Context, operations db.SQLOperations, todo *entities.Todo) error
 		TodoByID(ctx context.Context, operations db.SQLOperations, todoID int64) (*entities.Todo, error)
 		Todos(ctx context.Context, operations db.SQLOperations, filter *forms.Filter) ([]*entities.Todo, error)
+		TodosByIDs(ctx context.Context, operations db.SQLOperations, todoIDs []int64) ([]*entities.Todo, error)
 	}
 
 	todoRepository struct{}
@@ -41,17 +74,35 @@ func (r *todoRepository) Save(
 ) error {
 
 	todo.Touch()
+	todo.SyncStatus()
 
 	if todo.IsNew() {
 
+		if todo.Occurrence < 1 {
+			todo.Occurrence = 1
+		}
+
 		err := operations.QueryRowContext(
 			ctx,
 			insertTodoSQL,
+			todo.OwnerID,
+			todo.ProjectID,
+			todo.WorkspaceID,
+			todo.AssigneeID,
 			todo.Title,
 			todo.Description,
+			todo.Status,
+			todo.Completed,
+			todo.CompletedAt,
+			todo.DueAt,
+			todo.Priority,
+			todo.RecurrenceRule,
+			todo.SeriesID,
+			todo.SeriesStartAt,
+			todo.Occurrence,
 			todo.CreatedAt,
 			todo.UpdatedAt,
-		).Scan(&todo.ID)
+		).Scan(&todo.ID, &todo.Version)
 		if err != nil {
 			return apperror.NewDatabaseError(err)
 		}
@@ -59,21 +110,31 @@ func (r *todoRepository) Save(
 		return nil
 	}
 
-	_, err := operations.ExecContext(
+	result, err := operations.ExecContext(
 		ctx,
 		updateTodoSQL,
+		todo.ProjectID,
+		todo.AssigneeID,
 		todo.Title,
 		todo.Description,
+		todo.Status,
 		todo.Completed,
 		todo.CompletedAt,
+		todo.DueAt,
+		todo.Priority,
+		todo.RecurrenceRule,
+		todo.SeriesID,
+		todo.SeriesStartAt,
+		todo.Occurrence,
 		todo.UpdatedAt,
 		todo.ID,
+		todo.Version,
 	)
 	if err != nil {
 		return apperror.NewDatabaseError(err)
 	}
 
-	return nil
+	return r.nextVersion(result, todo)
 }
 
 func (r *todoRepository) TodoByID(
@@ -81,14 +142,16 @@ func (r *todoRepository) TodoByID(
 	operations db.SQLOperations,
 	todoID int64,
 ) (*entities.Todo, error) {
+	return r.todoByID(ctx, operations, getTodoByIDSQL, todoID)
+}
 
-	row := operations.QueryRowContext(
-		ctx,
-		getTodoByIDSQL,
-		todoID,
-	)
-
-	return r.scanRow(row)
+// DeletedTodoByID only finds todos that are in the trash.
+func (r *todoRepository) DeletedTodoByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+) (*entities.Todo, error) {
+	return r.todoByID(ctx, operations, getDeletedTodoByIDSQL, todoID)
 }
 
 func (r *todoRepository) Todos(
@@ -97,15 +160,35 @@ func (r *todoRepository) Todos(
 	filter *forms.Filter,
 ) ([]*entities.Todo, error) {
 
-	query := selectTodoSQL
-	args := []any{}
+	q := r.filterQuery(filter)
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+
+	if filter.Cursor != nil {
+
+		condition, err := r.cursorCondition(q, filter.Sort, filter.Cursor)
+		if err != nil {
+			return []*entities.Todo{}, err
+		}
+
+		q.where(condition)
+	}
+
+	orderBy, err := r.orderBy(filter.Sort, backward)
+	if err != nil {
+		return []*entities.Todo{}, err
+	}
+
+	query := selectTodoSQL + q.whereClause() + orderBy
 
-	if filter.Per > 0 && filter.Page > 0 {
-		query += " LIMIT $1 OFFSET $2"
-		args = append(args, filter.Per, (filter.Page-1)*filter.Per)
+	if filter.Limit > 0 {
+		// one extra row tells the caller whether another page exists
+		query += " LIMIT " + q.arg(filter.Limit+1)
+	} else if filter.Per > 0 && filter.Page > 0 {
+		query += fmt.Sprintf(" LIMIT %v OFFSET %v", q.arg(filter.Per), q.arg((filter.Page-1)*filter.Per))
 	}
 
-	rows, err := operations.QueryContext(ctx, query, args...)
+	rows, err := operations.QueryContext(ctx, query, q.args...)
 	if err != nil {
 		return []*entities.Todo{}, apperror.NewDatabaseError(err)
 	}
@@ -127,6 +210,15 @@ func (r *todoRepository) Todos(
 		return []*entities.Todo{}, apperror.NewDatabaseError(err)
 	}
 
+	if backward {
+		slices.Reverse(todos)
+	}
+
+	err = r.loadRelations(ctx, operations, todos)
+	if err != nil {
+		return []*entities.Todo{}, err
+	}
+
 	return todos, nil
 }
 
@@ -136,15 +228,16 @@ func (r *todoRepository) NumberOfTodos(
 	filter *forms.Filter,
 ) (int, error) {
 
-	query := countTodoSQL
-	args := []any{}
+	q := r.filterQuery(filter)
+
+	query := countTodoSQL + q.whereClause()
 
 	var count int
 
 	err := operations.QueryRowContext(
 		ctx,
 		query,
-		args...,
+		q.args...,
 	).Scan(&count)
 	if err != nil {
 		return 0, apperror.NewDatabaseError(err)
@@ -153,24 +246,518 @@ func (r *todoRepository) NumberOfTodos(
 	return count, nil
 }
 
-func (s *todoRepository) DeleteTodo(
+// BumpVersion moves the todo to a new version when something shown with it,
+// such as its checklist, changed without the todo itself being saved.
+func (r *todoRepository) BumpVersion(
 	ctx context.Context,
 	operations db.SQLOperations,
-	todoID int64,
+	todo *entities.Todo,
 ) error {
 
-	_, err := operations.ExecContext(
+	updatedAt := time.Now()
+
+	err := operations.QueryRowContext(
+		ctx,
+		bumpTodoVersionSQL,
+		updatedAt,
+		todo.ID,
+	).Scan(&todo.Version)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	todo.UpdatedAt = updatedAt
+
+	return nil
+}
+
+// DeleteTodo moves the todo to the trash; PurgeTodos removes it for good.
+func (r *todoRepository) DeleteTodo(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todo *entities.Todo,
+) error {
+
+	deletedAt := time.Now()
+
+	result, err := operations.ExecContext(
 		ctx,
 		deleteTodoSQL,
+		deletedAt,
+		todo.ID,
+		todo.Version,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	err = r.nextVersion(result, todo)
+	if err != nil {
+		return err
+	}
+
+	todo.DeletedAt = &deletedAt
+
+	return nil
+}
+
+// LockTodo holds the todo's row until the transaction behind operations
+// ends, so writes to what belongs to the todo wait for each other.
+func (r *todoRepository) LockTodo(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoID int64,
+) error {
+
+	var lockedID int64
+
+	err := operations.QueryRowContext(
+		ctx,
+		lockTodoSQL,
 		todoID,
+	).Scan(&lockedID)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *todoRepository) RestoreTodo(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todo *entities.Todo,
+) error {
+
+	result, err := operations.ExecContext(
+		ctx,
+		restoreTodoSQL,
+		todo.ID,
+		todo.Version,
 	)
 	if err != nil {
 		return apperror.NewDatabaseError(err)
 	}
 
+	err = r.nextVersion(result, todo)
+	if err != nil {
+		return err
+	}
+
+	todo.DeletedAt = nil
+
 	return nil
 }
 
+// TodosByIDs leaves out todos that no longer exist or are in the trash.
+func (r *todoRepository) TodosByIDs(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoIDs []int64,
+) ([]*entities.Todo, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getTodosByIDsSQL,
+		pq.Array(todoIDs),
+	)
+	if err != nil {
+		return []*entities.Todo{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	todos := make([]*entities.Todo, 0)
+
+	for rows.Next() {
+		todo, err := r.scanRow(rows)
+		if err != nil {
+			return []*entities.Todo{}, err
+		}
+
+		todos = append(todos, todo)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.Todo{}, apperror.NewDatabaseError(err)
+	}
+
+	err = r.loadRelations(ctx, operations, todos)
+	if err != nil {
+		return []*entities.Todo{}, err
+	}
+
+	return todos, nil
+}
+
+// PurgeableTodoIDs returns the todos that were moved to the trash before
+// deletedBefore, oldest first. They stay locked until the transaction behind
+// operations ends, and todos another purge has locked are skipped.
+func (r *todoRepository) PurgeableTodoIDs(
+	ctx context.Context,
+	operations db.SQLOperations,
+	deletedBefore time.Time,
+	limit int,
+) ([]int64, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getPurgeableTodoIDsSQL,
+		deletedBefore,
+		limit,
+	)
+	if err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	todoIDs := make([]int64, 0)
+
+	for rows.Next() {
+
+		var todoID int64
+
+		err := rows.Scan(&todoID)
+		if err != nil {
+			return []int64{}, apperror.NewDatabaseError(err)
+		}
+
+		todoIDs = append(todoIDs, todoID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	return todoIDs, nil
+}
+
+// PurgeTodos permanently removes trashed todos together with everything
+// that references them and returns the ids of those it removed. Todos that
+// are not in the trash are left alone.
+func (r *todoRepository) PurgeTodos(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todoIDs []int64,
+) ([]int64, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		purgeTodosSQL,
+		pq.Array(todoIDs),
+	)
+	if err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	purgedIDs := make([]int64, 0, len(todoIDs))
+
+	for rows.Next() {
+
+		var todoID int64
+
+		err := rows.Scan(&todoID)
+		if err != nil {
+			return []int64{}, apperror.NewDatabaseError(err)
+		}
+
+		purgedIDs = append(purgedIDs, todoID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []int64{}, apperror.NewDatabaseError(err)
+	}
+
+	return purgedIDs, nil
+}
+
+func (r *todoRepository) filterQuery(
+	filter *forms.Filter,
+) *queryBuilder {
+
+	q := &queryBuilder{}
+
+	if filter.Deleted {
+		q.where("deleted_at IS NOT NULL")
+	} else {
+		q.where("deleted_at IS NULL")
+	}
+
+	if filter.WorkspaceID > 0 {
+		q.where("workspace_id = " + q.arg(filter.WorkspaceID))
+	} else if filter.OwnerID > 0 && filter.IncludeWorkspaces {
+		ownerID := q.arg(filter.OwnerID)
+		q.where(fmt.Sprintf("((owner_id = %v AND workspace_id IS NULL) OR workspace_id IN (%v))", ownerID, fmt.Sprintf(selectMemberWorkspaceIDsSQL, ownerID)))
+	} else if filter.OwnerID > 0 {
+		// personal listings leave out the owner's todos in shared workspaces
+		q.where("owner_id = " + q.arg(filter.OwnerID) + " AND workspace_id IS NULL")
+	}
+
+	if filter.Unassigned {
+		q.where("assignee_id IS NULL")
+	} else if filter.AssigneeID > 0 {
+		q.where("assignee_id = " + q.arg(filter.AssigneeID))
+	}
+
+	if filter.Completed != nil {
+		q.where("completed = " + q.arg(*filter.Completed))
+	}
+
+	if term := strings.TrimSpace(filter.Term); term != "" {
+		pattern := q.arg("%" + escapeLike(term) + "%")
+		q.where(fmt.Sprintf("(title ILIKE %v OR description ILIKE %v)", pattern, pattern))
+	}
+
+	if filter.CreatedAfter != nil {
+		q.where("created_at >= " + q.arg(*filter.CreatedAfter))
+	}
+
+	if filter.CreatedBefore != nil {
+		q.where("created_at < " + q.arg(*filter.CreatedBefore))
+	}
+
+	if filter.CompletedAfter != nil {
+		q.where("completed_at >= " + q.arg(*filter.CompletedAfter))
+	}
+
+	if filter.CompletedBefore != nil {
+		q.where("completed_at < " + q.arg(*filter.CompletedBefore))
+	}
+
+	if filter.DueAfter != nil {
+		q.where("due_at >= " + q.arg(*filter.DueAfter))
+	}
+
+	if filter.DueBefore != nil {
+		q.where("due_at < " + q.arg(*filter.DueBefore))
+	}
+
+	if len(filter.Priorities) > 0 {
+
+		priorities := make([]int64, 0, len(filter.Priorities))
+		for _, priority := range filter.Priorities {
+			priorities = append(priorities, int64(priority))
+		}
+
+		q.where("priority = ANY(" + q.arg(pq.Array(priorities)) + ")")
+	}
+
+	if len(filter.Statuses) > 0 {
+
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+
+		q.where("status = ANY(" + q.arg(pq.Array(statuses)) + ")")
+	}
+
+	if filter.ProjectID > 0 {
+		q.where("project_id = " + q.arg(filter.ProjectID))
+	} else if !filter.IncludeArchived {
+		q.where("(project_id IS NULL OR project_id NOT IN (SELECT id FROM projects WHERE archived_at IS NOT NULL))")
+	}
+
+	if filter.SeriesID > 0 {
+		q.where("series_id = " + q.arg(filter.SeriesID))
+	}
+
+	if filter.Overdue {
+		q.where("status NOT IN ('done', 'cancelled') AND due_at < clock_timestamp()")
+	}
+
+	if len(filter.Tags) > 0 {
+
+		// tag names refer to the caller's own tags, as they do everywhere
+		// else, even on shared todos that other members have tagged too
+		taggedTodoIDs := fmt.Sprintf(selectTaggedTodoIDsSQL, q.arg(filter.OwnerID), q.arg(pq.Array(filter.Tags)))
+
+		if filter.TagMatch == forms.TagMatchAll {
+			tags := slices.Clone(filter.Tags)
+			slices.Sort(tags)
+			taggedTodoIDs += " GROUP BY todo_tags.todo_id HAVING COUNT(DISTINCT tags.name) = " + q.arg(len(slices.Compact(tags)))
+		}
+
+		q.where("id IN (" + taggedTodoIDs + ")")
+	}
+
+	return q
+}
+
+func (r *todoRepository) orderBy(
+	sortFields []forms.SortField,
+	backward bool,
+) (string, error) {
+
+	columns := make([]string, 0, len(sortFields)+1)
+
+	for _, sortField := range append(slices.Clone(sortFields), forms.SortField{Field: "id"}) {
+
+		column, err := r.sortColumn(sortField.Field)
+		if err != nil {
+			return "", err
+		}
+
+		if sortField.Descending != backward {
+			column += " DESC"
+		}
+
+		columns = append(columns, column)
+	}
+
+	return " ORDER BY " + strings.Join(columns, ", "), nil
+}
+
+// cursorCondition expands a keyset position into row comparisons, one per
+// sort column, so mixed sort directions are handled without row values.
+func (r *todoRepository) cursorCondition(
+	q *queryBuilder,
+	sortFields []forms.SortField,
+	cursor *forms.Cursor,
+) (string, error) {
+
+	if len(cursor.Values) != len(sortFields) {
+		return "", errors.New("invalid cursor")
+	}
+
+	values := append(slices.Clone(cursor.Values), strconv.FormatInt(cursor.ID, 10))
+	sortFields = append(slices.Clone(sortFields), forms.SortField{Field: "id"})
+
+	columns := make([]string, len(sortFields))
+	placeholders := make([]string, len(sortFields))
+
+	for i, sortField := range sortFields {
+
+		column, err := r.sortColumn(sortField.Field)
+		if err != nil {
+			return "", err
+		}
+
+		columns[i] = column
+		placeholders[i] = q.arg(values[i])
+	}
+
+	clauses := make([]string, 0, len(sortFields))
+
+	for i, sortField := range sortFields {
+
+		parts := make([]string, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j]+" = "+placeholders[j])
+		}
+
+		operator := " > "
+		if sortField.Descending != cursor.Backward {
+			operator = " < "
+		}
+
+		parts = append(parts, columns[i]+operator+placeholders[i])
+
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(clauses, " OR ") + ")", nil
+}
+
+func (r *todoRepository) sortColumn(
+	field string,
+) (string, error) {
+
+	if field == "id" {
+		return "id", nil
+	}
+
+	column, ok := todoSortColumns[field]
+	if !ok {
+		return "", fmt.Errorf("invalid sort field %v", field)
+	}
+
+	return column, nil
+}
+
+// loadRelations fills in the tags and checklist counts of every todo with one
+// query each rather than one per todo.
+func (r *todoRepository) loadRelations(
+	ctx context.Context,
+	operations db.SQLOperations,
+	todos []*entities.Todo,
+) error {
+
+	todoIDs := make([]int64, 0, len(todos))
+	for _, todo := range todos {
+		todoIDs = append(todoIDs, todo.ID)
+	}
+
+	tagsByTodo, err := NewTagRepository().TagsForTodos(ctx, operations, todoIDs)
+	if err != nil {
+		return err
+	}
+
+	itemCounts, err := NewTodoItemRepository().ItemCountsForTodos(ctx, operations, todoIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, todo := range todos {
+		todo.Tags = tagsByTodo[todo.ID]
+		todo.SetItemCounts(itemCounts[todo.ID])
+	}
+
+	return nil
+}
+
+// nextVersion moves the todo to its next version once an update guarded by
+// its current version went through. No affected row means another write got
+// there first.
+func (r *todoRepository) nextVersion(
+	result sql.Result,
+	todo *entities.Todo,
+) error {
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	if rowsAffected == 0 {
+		return apperror.NewConflictError()
+	}
+
+	todo.Version++
+
+	return nil
+}
+
+func (r *todoRepository) todoByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	query string,
+	todoID int64,
+) (*entities.Todo, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		query,
+		todoID,
+	)
+
+	todo, err := r.scanRow(row)
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	err = r.loadRelations(ctx, operations, []*entities.Todo{todo})
+	if err != nil {
+		return &entities.Todo{}, err
+	}
+
+	return todo, nil
+}
+
 func (r *todoRepository) scanRow(
 	rowScanner db.RowScanner,
 ) (*entities.Todo, error) {
@@ -179,10 +766,23 @@ func (r *todoRepository) scanRow(
 
 	err := rowScanner.Scan(
 		&todo.ID,
+		&todo.OwnerID,
+		&todo.ProjectID,
+		&todo.WorkspaceID,
+		&todo.AssigneeID,
 		&todo.Title,
 		&todo.Description,
+		&todo.Status,
 		&todo.Completed,
 		&todo.CompletedAt,
+		&todo.DueAt,
+		&todo.Priority,
+		&todo.RecurrenceRule,
+		&todo.SeriesID,
+		&todo.SeriesStartAt,
+		&todo.Occurrence,
+		&todo.DeletedAt,
+		&todo.Version,
 		&todo.CreatedAt,
 		&todo.UpdatedAt,
 	)
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
+	"github.com/ernestngugi/todo/internal/apperror"
 	"github.com/ernestngugi/todo/internal/db"
 	"github.com/ernestngugi/todo/internal/entities"
 	"github.com/ernestngugi/todo/internal/forms"
@@ -24,9 +27,13 @@ func TestTodoRepository(t *testing.T) {
 
 		todoRepository := NewTodoRepository()
 
+		user, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
 		Convey("can save a todo", func() {
 
 			todo := &entities.Todo{
+				OwnerID:     user.ID,
 				Title:       "Test",
 				Description: "description",
 			}
@@ -41,7 +48,7 @@ func TestTodoRepository(t *testing.T) {
 
 		Convey("can get todo by id", func() {
 
-			todo, err := CreateTodo(ctx, dB)
+			todo, err := CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			fmt.Printf("todo %+v", todo)
@@ -50,6 +57,7 @@ func TestTodoRepository(t *testing.T) {
 			So(err, ShouldBeNil)
 
 			So(foundTodo.ID, ShouldEqual, todo.ID)
+			So(foundTodo.OwnerID, ShouldEqual, user.ID)
 			So(foundTodo.Title, ShouldEqual, todo.Title)
 			So(foundTodo.Description, ShouldEqual, todo.Description)
 			So(foundTodo.Completed, ShouldBeFalse)
@@ -60,7 +68,7 @@ func TestTodoRepository(t *testing.T) {
 
 		Convey("can update a todo", func() {
 
-			todo, err := CreateTodo(ctx, dB)
+			todo, err := CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			timeNow := time.Now()
@@ -85,10 +93,10 @@ func TestTodoRepository(t *testing.T) {
 
 		Convey("can get todo page 1 per 1", func() {
 
-			todo, err := CreateTodo(ctx, dB)
+			todo, err := CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
-			_, err = CreateTodo(ctx, dB)
+			_, err = CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			foundTodos, err := todoRepository.Todos(ctx, dB, &forms.Filter{Page: 1, Per: 1})
@@ -107,10 +115,10 @@ func TestTodoRepository(t *testing.T) {
 
 		Convey("can get todo page 2 per 1", func() {
 
-			_, err := CreateTodo(ctx, dB)
+			_, err := CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
-			todo, err := CreateTodo(ctx, dB)
+			todo, err := CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			foundTodos, err := todoRepository.Todos(ctx, dB, &forms.Filter{Page: 2, Per: 1})
@@ -129,16 +137,396 @@ func TestTodoRepository(t *testing.T) {
 
 		Convey("can delete a todo", func() {
 
-			todo, err := CreateTodo(ctx, dB)
+			todo, err := CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
-			err = todoRepository.DeleteTodo(ctx, dB, todo.ID)
+			err = todoRepository.DeleteTodo(ctx, dB, todo)
 			So(err, ShouldBeNil)
 
 			_, err = todoRepository.TodoByID(ctx, dB, todo.ID)
 			So(err, ShouldNotBeNil)
 
 			So(err.Error(), ShouldContainSubstring, "sql: no rows in result set")
+
+			deletedTodo, err := todoRepository.DeletedTodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(deletedTodo.DeletedAt, ShouldNotBeNil)
+		})
+
+		Convey("leaves deleted todos out of listings and counts", func() {
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = todoRepository.DeleteTodo(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			count, err := todoRepository.NumberOfTodos(ctx, dB, &forms.Filter{OwnerID: user.ID})
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 1)
+
+			trash, err := todoRepository.Todos(ctx, dB, &forms.Filter{OwnerID: user.ID, Deleted: true})
+			So(err, ShouldBeNil)
+
+			So(len(trash), ShouldEqual, 1)
+			So(trash[0].ID, ShouldEqual, todo.ID)
+		})
+
+		Convey("only purges todos deleted before the cutoff", func() {
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = todoRepository.DeleteTodo(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			todoIDs, err := todoRepository.PurgeableTodoIDs(ctx, dB, todo.DeletedAt.Add(-time.Minute), 10)
+			So(err, ShouldBeNil)
+
+			So(len(todoIDs), ShouldEqual, 0)
+
+			todoIDs, err = todoRepository.PurgeableTodoIDs(ctx, dB, todo.DeletedAt.Add(time.Minute), 10)
+			So(err, ShouldBeNil)
+
+			So(todoIDs, ShouldResemble, []int64{todo.ID})
+
+			purgedIDs, err := todoRepository.PurgeTodos(ctx, dB, todoIDs)
+			So(err, ShouldBeNil)
+
+			So(purgedIDs, ShouldResemble, []int64{todo.ID})
+
+			_, err = todoRepository.DeletedTodoByID(ctx, dB, todo.ID)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can filter todos by owner", func() {
+
+			otherUser, err := CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodo(ctx, dB, otherUser)
+			So(err, ShouldBeNil)
+
+			filter := &forms.Filter{OwnerID: user.ID}
+
+			foundTodos, err := todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 1)
+			So(foundTodos[0].ID, ShouldEqual, todo.ID)
+
+			count, err := todoRepository.NumberOfTodos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 1)
+		})
+
+		Convey("can filter todos by completion", func() {
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			completedTodo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			timeNow := time.Now()
+			completedTodo.Completed = true
+			completedTodo.CompletedAt = &timeNow
+
+			err = todoRepository.Save(ctx, dB, completedTodo)
+			So(err, ShouldBeNil)
+
+			completed := false
+			filter := &forms.Filter{OwnerID: user.ID, Completed: &completed}
+
+			foundTodos, err := todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 1)
+			So(foundTodos[0].ID, ShouldEqual, todo.ID)
+
+			count, err := todoRepository.NumberOfTodos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 1)
+
+			completedAfter := timeNow.Add(-time.Minute)
+			filter = &forms.Filter{OwnerID: user.ID, CompletedAfter: &completedAfter}
+
+			foundTodos, err = todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 1)
+			So(foundTodos[0].ID, ShouldEqual, completedTodo.ID)
+		})
+
+		Convey("can search todos by title and description", func() {
+
+			todo := &entities.Todo{
+				OwnerID:     user.ID,
+				Title:       "groceries",
+				Description: "buy 100% organic milk",
+			}
+
+			err := todoRepository.Save(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			for _, term := range []string{"GROCER", "100%", "organic milk"} {
+
+				filter := &forms.Filter{OwnerID: user.ID, Term: term}
+
+				foundTodos, err := todoRepository.Todos(ctx, dB, filter)
+				So(err, ShouldBeNil)
+
+				So(len(foundTodos), ShouldEqual, 1)
+				So(foundTodos[0].ID, ShouldEqual, todo.ID)
+
+				count, err := todoRepository.NumberOfTodos(ctx, dB, filter)
+				So(err, ShouldBeNil)
+
+				So(count, ShouldEqual, 1)
+			}
+
+			foundTodos, err := todoRepository.Todos(ctx, dB, &forms.Filter{OwnerID: user.ID, Term: "1_0"})
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 0)
+		})
+
+		Convey("can filter todos by creation date with pagination", func() {
+
+			_, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			createdBefore := time.Now().Add(time.Minute)
+			filter := &forms.Filter{OwnerID: user.ID, CreatedBefore: &createdBefore, Page: 1, Per: 1}
+
+			foundTodos, err := todoRepository.Todos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 1)
+
+			count, err := todoRepository.NumberOfTodos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 2)
+
+			createdAfter := time.Now().Add(time.Minute)
+			filter = &forms.Filter{OwnerID: user.ID, CreatedAfter: &createdAfter}
+
+			count, err = todoRepository.NumberOfTodos(ctx, dB, filter)
+			So(err, ShouldBeNil)
+
+			So(count, ShouldEqual, 0)
+		})
+
+		Convey("can sort todos", func() {
+
+			titles := []string{"bravo", "alpha", "charlie", "alpha"}
+			todos := make([]*entities.Todo, 0, len(titles))
+
+			for _, title := range titles {
+
+				todo := &entities.Todo{
+					OwnerID:     user.ID,
+					Title:       title,
+					Description: "description",
+				}
+
+				err := todoRepository.Save(ctx, dB, todo)
+				So(err, ShouldBeNil)
+
+				todos = append(todos, todo)
+			}
+
+			foundTodos, err := todoRepository.Todos(ctx, dB, &forms.Filter{
+				OwnerID: user.ID,
+				Sort:    []forms.SortField{{Field: forms.SortFieldTitle}},
+			})
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 4)
+			So(foundTodos[0].ID, ShouldEqual, todos[1].ID)
+			So(foundTodos[1].ID, ShouldEqual, todos[3].ID)
+			So(foundTodos[2].ID, ShouldEqual, todos[0].ID)
+			So(foundTodos[3].ID, ShouldEqual, todos[2].ID)
+
+			foundTodos, err = todoRepository.Todos(ctx, dB, &forms.Filter{
+				OwnerID: user.ID,
+				Sort:    []forms.SortField{{Field: forms.SortFieldCreatedAt, Descending: true}},
+			})
+			So(err, ShouldBeNil)
+
+			So(foundTodos[0].ID, ShouldEqual, todos[3].ID)
+			So(foundTodos[3].ID, ShouldEqual, todos[0].ID)
+		})
+
+		Convey("cannot sort todos by an unknown field", func() {
+
+			_, err := todoRepository.Todos(ctx, dB, &forms.Filter{
+				OwnerID: user.ID,
+				Sort:    []forms.SortField{{Field: "id; DROP TABLE todos"}},
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("can filter overdue todos and by due date", func() {
+
+			yesterday := time.Now().Add(-24 * time.Hour)
+			tomorrow := time.Now().Add(24 * time.Hour)
+
+			overdueTodo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			overdueTodo.DueAt = &yesterday
+
+			err = todoRepository.Save(ctx, dB, overdueTodo)
+			So(err, ShouldBeNil)
+
+			upcomingTodo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			upcomingTodo.DueAt = &tomorrow
+
+			err = todoRepository.Save(ctx, dB, upcomingTodo)
+			So(err, ShouldBeNil)
+
+			_, err = CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			foundTodos, err := todoRepository.Todos(ctx, dB, &forms.Filter{OwnerID: user.ID, Overdue: true})
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 1)
+			So(foundTodos[0].ID, ShouldEqual, overdueTodo.ID)
+
+			dueAfter := time.Now()
+
+			foundTodos, err = todoRepository.Todos(ctx, dB, &forms.Filter{OwnerID: user.ID, DueAfter: &dueAfter})
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 1)
+			So(foundTodos[0].ID, ShouldEqual, upcomingTodo.ID)
+
+			foundTodos, err = todoRepository.Todos(ctx, dB, &forms.Filter{
+				OwnerID: user.ID,
+				Sort:    []forms.SortField{{Field: forms.SortFieldDueAt}},
+			})
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 3)
+			So(foundTodos[0].ID, ShouldEqual, overdueTodo.ID)
+			So(foundTodos[1].ID, ShouldEqual, upcomingTodo.ID)
+			So(foundTodos[2].DueAt, ShouldBeNil)
+		})
+
+		Convey("leaves cancelled todos out of overdue listings", func() {
+
+			yesterday := time.Now().Add(-24 * time.Hour)
+
+			overdueTodo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			overdueTodo.DueAt = &yesterday
+			overdueTodo.SetStatus(entities.TodoStatusBlocked, time.Now())
+
+			err = todoRepository.Save(ctx, dB, overdueTodo)
+			So(err, ShouldBeNil)
+
+			cancelledTodo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			cancelledTodo.DueAt = &yesterday
+			cancelledTodo.SetStatus(entities.TodoStatusCancelled, time.Now())
+
+			err = todoRepository.Save(ctx, dB, cancelledTodo)
+			So(err, ShouldBeNil)
+
+			foundTodos, err := todoRepository.Todos(ctx, dB, &forms.Filter{OwnerID: user.ID, Overdue: true})
+			So(err, ShouldBeNil)
+
+			So(len(foundTodos), ShouldEqual, 1)
+			So(foundTodos[0].ID, ShouldEqual, overdueTodo.ID)
+		})
+
+		Convey("refuses to save a todo that was changed after it was read", func() {
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			So(todo.Version, ShouldEqual, 1)
+
+			staleTodo, err := todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			todo.Title = "first"
+
+			err = todoRepository.Save(ctx, dB, todo)
+			So(err, ShouldBeNil)
+
+			So(todo.Version, ShouldEqual, 2)
+
+			staleTodo.Title = "second"
+
+			err = todoRepository.Save(ctx, dB, staleTodo)
+			So(err, ShouldNotBeNil)
+
+			So(errors.Is(err, apperror.ErrConflict), ShouldBeTrue)
+			So(apperror.Wrap(err).HttpStatusCode(), ShouldEqual, http.StatusConflict)
+
+			foundTodo, err := todoRepository.TodoByID(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			So(foundTodo.Title, ShouldEqual, "first")
+			So(foundTodo.Version, ShouldEqual, 2)
+		})
+
+		Convey("can get todos by their ids", func() {
+
+			todo1, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			todo2, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			deletedTodo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = todoRepository.DeleteTodo(ctx, dB, deletedTodo)
+			So(err, ShouldBeNil)
+
+			todos, err := todoRepository.TodosByIDs(ctx, dB, []int64{todo2.ID, todo1.ID, deletedTodo.ID})
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 2)
+			So(todos[0].ID, ShouldEqual, todo1.ID)
+			So(todos[1].ID, ShouldEqual, todo2.ID)
+		})
+
+		Convey("can lock a todo that exists", func() {
+
+			todo, err := CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			err = todoRepository.LockTodo(ctx, dB, todo.ID)
+			So(err, ShouldBeNil)
+
+			err = todoRepository.LockTodo(ctx, dB, todo.ID+1000)
+			So(err, ShouldNotBeNil)
 		})
 	}))
 }
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	getUserByEmailSQL = selectUserSQL + " WHERE email = $1"
+	getUserByIDSQL    = selectUserSQL + " WHERE id = $1"
+	insertUserSQL     = "INSERT INTO users (email, password_hash, created_at, updated_at) VALUES ($1, $2, $3, $4) RETURNING id"
+	selectUserSQL     = "SELECT id, email, password_hash, created_at, updated_at FROM users"
+	updateUserSQL     = "UPDATE users SET email = $1, password_hash = $2, updated_at = $3 WHERE id = $4"
+)
+
+type (
+	UserRepository interface {
+		Save(ctx context.Context, operations db.SQLOperations, user *entities.User) error
+		UserByEmail(ctx context.Context, operations db.SQLOperations, email string) (*entities.User, error)
+		UserByID(ctx context.Context, operations db.SQLOperations, userID int64) (*entities.User, error)
+	}
+
+	userRepository struct{}
+)
+
+func NewUserRepository() UserRepository {
+	return &userRepository{}
+}
+
+func (r *userRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	user *entities.User,
+) error {
+
+	user.Touch()
+
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+
+	if user.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertUserSQL,
+			user.Email,
+			user.PasswordHash,
+			user.CreatedAt,
+			user.UpdatedAt,
+		).Scan(&user.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateUserSQL,
+		user.Email,
+		user.PasswordHash,
+		user.UpdatedAt,
+		user.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *userRepository) UserByEmail(
+	ctx context.Context,
+	operations db.SQLOperations,
+	email string,
+) (*entities.User, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getUserByEmailSQL,
+		strings.ToLower(strings.TrimSpace(email)),
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *userRepository) UserByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	userID int64,
+) (*entities.User, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getUserByIDSQL,
+		userID,
+	)
+
+	return r.scanRow(row)
+}
+
+func (r *userRepository) scanRow(
+	rowScanner db.RowScanner,
+) (*entities.User, error) {
+
+	var user entities.User
+
+	err := rowScanner.Scan(
+		&user.ID,
+		&user.Email,
+		&user.PasswordHash,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.User{}, apperror.NewDatabaseError(err)
+	}
+
+	return &user, nil
+}
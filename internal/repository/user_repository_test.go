@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUserRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestUserRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		userRepository := NewUserRepository()
+
+		Convey("can save a user", func() {
+
+			user := &entities.User{
+				Email:        " Test@Example.com ",
+				PasswordHash: "hash",
+			}
+
+			err := userRepository.Save(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			So(user.ID, ShouldNotBeZeroValue)
+			So(user.Email, ShouldEqual, "test@example.com")
+			So(user.CreatedAt, ShouldNotBeZeroValue)
+			So(user.UpdatedAt, ShouldNotBeZeroValue)
+		})
+
+		Convey("can get user by id", func() {
+
+			user, err := CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			foundUser, err := userRepository.UserByID(ctx, dB, user.ID)
+			So(err, ShouldBeNil)
+
+			So(foundUser.ID, ShouldEqual, user.ID)
+			So(foundUser.Email, ShouldEqual, user.Email)
+			So(foundUser.PasswordHash, ShouldEqual, user.PasswordHash)
+		})
+
+		Convey("can get user by email", func() {
+
+			user, err := CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			foundUser, err := userRepository.UserByEmail(ctx, dB, user.Email)
+			So(err, ShouldBeNil)
+
+			So(foundUser.ID, ShouldEqual, user.ID)
+		})
+
+		Convey("cannot save two users with the same email", func() {
+
+			user, err := CreateUser(ctx, dB)
+			So(err, ShouldBeNil)
+
+			duplicateUser := &entities.User{
+				Email:        user.Email,
+				PasswordHash: "hash",
+			}
+
+			err = userRepository.Save(ctx, dB, duplicateUser)
+			So(err, ShouldNotBeNil)
+			So(apperror.IsUniqueViolation(err), ShouldBeTrue)
+		})
+	}))
+}
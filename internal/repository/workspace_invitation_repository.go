@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	getInvitationByHashSQL = selectInvitationSQL + " WHERE token_hash = $1"
+	insertInvitationSQL    = "INSERT INTO workspace_invitations (workspace_id, invited_by, email, role, token_hash, expires_at, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id"
+	selectInvitationSQL    = "SELECT id, workspace_id, invited_by, email, role, token_hash, expires_at, accepted_at, created_at, updated_at FROM workspace_invitations"
+	updateInvitationSQL    = "UPDATE workspace_invitations SET accepted_at = $1, updated_at = $2 WHERE id = $3"
+)
+
+type (
+	WorkspaceInvitationRepository interface {
+		InvitationByHash(ctx context.Context, operations db.SQLOperations, tokenHash string) (*entities.WorkspaceInvitation, error)
+		Save(ctx context.Context, operations db.SQLOperations, invitation *entities.WorkspaceInvitation) error
+	}
+
+	workspaceInvitationRepository struct{}
+)
+
+func NewWorkspaceInvitationRepository() WorkspaceInvitationRepository {
+	return &workspaceInvitationRepository{}
+}
+
+func (r *workspaceInvitationRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	invitation *entities.WorkspaceInvitation,
+) error {
+
+	invitation.Touch()
+
+	invitation.Email = strings.ToLower(strings.TrimSpace(invitation.Email))
+
+	if invitation.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertInvitationSQL,
+			invitation.WorkspaceID,
+			invitation.InvitedBy,
+			invitation.Email,
+			invitation.Role,
+			invitation.TokenHash,
+			invitation.ExpiresAt,
+			invitation.CreatedAt,
+			invitation.UpdatedAt,
+		).Scan(&invitation.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateInvitationSQL,
+		invitation.AcceptedAt,
+		invitation.UpdatedAt,
+		invitation.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *workspaceInvitationRepository) InvitationByHash(
+	ctx context.Context,
+	operations db.SQLOperations,
+	tokenHash string,
+) (*entities.WorkspaceInvitation, error) {
+
+	var invitation entities.WorkspaceInvitation
+
+	err := operations.QueryRowContext(
+		ctx,
+		getInvitationByHashSQL,
+		tokenHash,
+	).Scan(
+		&invitation.ID,
+		&invitation.WorkspaceID,
+		&invitation.InvitedBy,
+		&invitation.Email,
+		&invitation.Role,
+		&invitation.TokenHash,
+		&invitation.ExpiresAt,
+		&invitation.AcceptedAt,
+		&invitation.CreatedAt,
+		&invitation.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.WorkspaceInvitation{}, apperror.NewDatabaseError(err)
+	}
+
+	return &invitation, nil
+}
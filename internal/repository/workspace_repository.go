@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+const (
+	countWorkspaceOwnersSQL = "SELECT COUNT(user_id) FROM workspace_members WHERE workspace_id = $1 AND role = 'owner'"
+	deleteMemberSQL         = "DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2"
+	getMemberByUserSQL      = selectMemberSQL + " WHERE workspace_members.workspace_id = $1 AND workspace_members.user_id = $2"
+	getMembersSQL           = selectMemberSQL + " WHERE workspace_members.workspace_id = $1 ORDER BY users.email"
+	getWorkspaceByIDSQL     = "SELECT id, name, created_at, updated_at FROM workspaces WHERE id = $1"
+	getWorkspacesByUserSQL  = "SELECT workspaces.id, workspaces.name, workspaces.created_at, workspaces.updated_at, workspace_members.role FROM workspaces JOIN workspace_members ON workspace_members.workspace_id = workspaces.id WHERE workspace_members.user_id = $1 ORDER BY workspaces.name, workspaces.id"
+	insertWorkspaceSQL      = "INSERT INTO workspaces (name, created_at, updated_at) VALUES ($1, $2, $3) RETURNING id"
+	saveMemberSQL           = "INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at) VALUES ($1, $2, $3, $4, $5) ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role, updated_at = EXCLUDED.updated_at"
+	selectMemberSQL         = "SELECT workspace_members.workspace_id, workspace_members.user_id, users.email, workspace_members.role, workspace_members.created_at, workspace_members.updated_at FROM workspace_members JOIN users ON users.id = workspace_members.user_id"
+	updateWorkspaceSQL      = "UPDATE workspaces SET name = $1, updated_at = $2 WHERE id = $3"
+)
+
+type (
+	WorkspaceRepository interface {
+		DeleteMember(ctx context.Context, operations db.SQLOperations, workspaceID, userID int64) error
+		MemberByUser(ctx context.Context, operations db.SQLOperations, workspaceID, userID int64) (*entities.WorkspaceMember, error)
+		Members(ctx context.Context, operations db.SQLOperations, workspaceID int64) ([]*entities.WorkspaceMember, error)
+		NumberOfOwners(ctx context.Context, operations db.SQLOperations, workspaceID int64) (int, error)
+		Save(ctx context.Context, operations db.SQLOperations, workspace *entities.Workspace) error
+		SaveMember(ctx context.Context, operations db.SQLOperations, member *entities.WorkspaceMember) error
+		WorkspaceByID(ctx context.Context, operations db.SQLOperations, workspaceID int64) (*entities.Workspace, error)
+		WorkspacesByUser(ctx context.Context, operations db.SQLOperations, userID int64) ([]*entities.Workspace, error)
+	}
+
+	workspaceRepository struct{}
+)
+
+func NewWorkspaceRepository() WorkspaceRepository {
+	return &workspaceRepository{}
+}
+
+func (r *workspaceRepository) Save(
+	ctx context.Context,
+	operations db.SQLOperations,
+	workspace *entities.Workspace,
+) error {
+
+	workspace.Touch()
+
+	if workspace.IsNew() {
+
+		err := operations.QueryRowContext(
+			ctx,
+			insertWorkspaceSQL,
+			workspace.Name,
+			workspace.CreatedAt,
+			workspace.UpdatedAt,
+		).Scan(&workspace.ID)
+		if err != nil {
+			return apperror.NewDatabaseError(err)
+		}
+
+		return nil
+	}
+
+	_, err := operations.ExecContext(
+		ctx,
+		updateWorkspaceSQL,
+		workspace.Name,
+		workspace.UpdatedAt,
+		workspace.ID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+// SaveMember adds a member or changes the role of an existing one.
+func (r *workspaceRepository) SaveMember(
+	ctx context.Context,
+	operations db.SQLOperations,
+	member *entities.WorkspaceMember,
+) error {
+
+	member.Touch()
+
+	_, err := operations.ExecContext(
+		ctx,
+		saveMemberSQL,
+		member.WorkspaceID,
+		member.UserID,
+		member.Role,
+		member.CreatedAt,
+		member.UpdatedAt,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *workspaceRepository) DeleteMember(
+	ctx context.Context,
+	operations db.SQLOperations,
+	workspaceID int64,
+	userID int64,
+) error {
+
+	_, err := operations.ExecContext(
+		ctx,
+		deleteMemberSQL,
+		workspaceID,
+		userID,
+	)
+	if err != nil {
+		return apperror.NewDatabaseError(err)
+	}
+
+	return nil
+}
+
+func (r *workspaceRepository) MemberByUser(
+	ctx context.Context,
+	operations db.SQLOperations,
+	workspaceID int64,
+	userID int64,
+) (*entities.WorkspaceMember, error) {
+
+	row := operations.QueryRowContext(
+		ctx,
+		getMemberByUserSQL,
+		workspaceID,
+		userID,
+	)
+
+	return r.scanMember(row)
+}
+
+func (r *workspaceRepository) Members(
+	ctx context.Context,
+	operations db.SQLOperations,
+	workspaceID int64,
+) ([]*entities.WorkspaceMember, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getMembersSQL,
+		workspaceID,
+	)
+	if err != nil {
+		return []*entities.WorkspaceMember{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	members := make([]*entities.WorkspaceMember, 0)
+
+	for rows.Next() {
+		member, err := r.scanMember(rows)
+		if err != nil {
+			return []*entities.WorkspaceMember{}, err
+		}
+
+		members = append(members, member)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.WorkspaceMember{}, apperror.NewDatabaseError(err)
+	}
+
+	return members, nil
+}
+
+func (r *workspaceRepository) NumberOfOwners(
+	ctx context.Context,
+	operations db.SQLOperations,
+	workspaceID int64,
+) (int, error) {
+
+	var count int
+
+	err := operations.QueryRowContext(
+		ctx,
+		countWorkspaceOwnersSQL,
+		workspaceID,
+	).Scan(&count)
+	if err != nil {
+		return 0, apperror.NewDatabaseError(err)
+	}
+
+	return count, nil
+}
+
+func (r *workspaceRepository) WorkspaceByID(
+	ctx context.Context,
+	operations db.SQLOperations,
+	workspaceID int64,
+) (*entities.Workspace, error) {
+
+	var workspace entities.Workspace
+
+	err := operations.QueryRowContext(
+		ctx,
+		getWorkspaceByIDSQL,
+		workspaceID,
+	).Scan(
+		&workspace.ID,
+		&workspace.Name,
+		&workspace.CreatedAt,
+		&workspace.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.Workspace{}, apperror.NewDatabaseError(err)
+	}
+
+	return &workspace, nil
+}
+
+func (r *workspaceRepository) WorkspacesByUser(
+	ctx context.Context,
+	operations db.SQLOperations,
+	userID int64,
+) ([]*entities.Workspace, error) {
+
+	rows, err := operations.QueryContext(
+		ctx,
+		getWorkspacesByUserSQL,
+		userID,
+	)
+	if err != nil {
+		return []*entities.Workspace{}, apperror.NewDatabaseError(err)
+	}
+
+	defer rows.Close()
+
+	workspaces := make([]*entities.Workspace, 0)
+
+	for rows.Next() {
+
+		var workspace entities.Workspace
+
+		err := rows.Scan(
+			&workspace.ID,
+			&workspace.Name,
+			&workspace.CreatedAt,
+			&workspace.UpdatedAt,
+			&workspace.Role,
+		)
+		if err != nil {
+			return []*entities.Workspace{}, apperror.NewDatabaseError(err)
+		}
+
+		workspaces = append(workspaces, &workspace)
+	}
+
+	if err := rows.Err(); err != nil {
+		return []*entities.Workspace{}, apperror.NewDatabaseError(err)
+	}
+
+	return workspaces, nil
+}
+
+func (r *workspaceRepository) scanMember(
+	rowScanner db.RowScanner,
+) (*entities.WorkspaceMember, error) {
+
+	var member entities.WorkspaceMember
+
+	err := rowScanner.Scan(
+		&member.WorkspaceID,
+		&member.UserID,
+		&member.Email,
+		&member.Role,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+	)
+	if err != nil {
+		return &entities.WorkspaceMember{}, apperror.NewDatabaseError(err)
+	}
+
+	return &member, nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/testutils"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestWorkspaceRepository(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	Convey("TestWorkspaceRepository", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		workspaceInvitationRepository := NewWorkspaceInvitationRepository()
+		workspaceRepository := NewWorkspaceRepository()
+		todoRepository := NewTodoRepository()
+
+		owner, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		member, err := CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		workspace, err := CreateWorkspace(ctx, dB, owner)
+		So(err, ShouldBeNil)
+
+		Convey("can add a member and change their role", func() {
+
+			err := workspaceRepository.SaveMember(ctx, dB, &entities.WorkspaceMember{
+				WorkspaceID: workspace.ID,
+				UserID:      member.ID,
+				Role:        entities.WorkspaceRoleViewer,
+			})
+			So(err, ShouldBeNil)
+
+			err = workspaceRepository.SaveMember(ctx, dB, &entities.WorkspaceMember{
+				WorkspaceID: workspace.ID,
+				UserID:      member.ID,
+				Role:        entities.WorkspaceRoleEditor,
+			})
+			So(err, ShouldBeNil)
+
+			foundMember, err := workspaceRepository.MemberByUser(ctx, dB, workspace.ID, member.ID)
+			So(err, ShouldBeNil)
+
+			So(foundMember.Role, ShouldEqual, entities.WorkspaceRoleEditor)
+			So(foundMember.Email, ShouldEqual, member.Email)
+
+			members, err := workspaceRepository.Members(ctx, dB, workspace.ID)
+			So(err, ShouldBeNil)
+
+			So(len(members), ShouldEqual, 2)
+
+			owners, err := workspaceRepository.NumberOfOwners(ctx, dB, workspace.ID)
+			So(err, ShouldBeNil)
+
+			So(owners, ShouldEqual, 1)
+		})
+
+		Convey("can list the workspaces of a user with their role", func() {
+
+			workspaces, err := workspaceRepository.WorkspacesByUser(ctx, dB, owner.ID)
+			So(err, ShouldBeNil)
+
+			So(len(workspaces), ShouldEqual, 1)
+			So(workspaces[0].ID, ShouldEqual, workspace.ID)
+			So(workspaces[0].Role, ShouldEqual, entities.WorkspaceRoleOwner)
+
+			workspaces, err = workspaceRepository.WorkspacesByUser(ctx, dB, member.ID)
+			So(err, ShouldBeNil)
+
+			So(len(workspaces), ShouldEqual, 0)
+		})
+
+		Convey("can remove a member", func() {
+
+			err := workspaceRepository.DeleteMember(ctx, dB, workspace.ID, owner.ID)
+			So(err, ShouldBeNil)
+
+			_, err = workspaceRepository.MemberByUser(ctx, dB, workspace.ID, owner.ID)
+			So(errors.Is(err, sql.ErrNoRows), ShouldBeTrue)
+		})
+
+		Convey("can find an invitation by its token hash", func() {
+
+			invitation := &entities.WorkspaceInvitation{
+				WorkspaceID: workspace.ID,
+				InvitedBy:   owner.ID,
+				Email:       " Member@Example.com ",
+				Role:        entities.WorkspaceRoleViewer,
+				TokenHash:   "token-hash",
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}
+
+			err := workspaceInvitationRepository.Save(ctx, dB, invitation)
+			So(err, ShouldBeNil)
+
+			foundInvitation, err := workspaceInvitationRepository.InvitationByHash(ctx, dB, "token-hash")
+			So(err, ShouldBeNil)
+
+			So(foundInvitation.ID, ShouldEqual, invitation.ID)
+			So(foundInvitation.Email, ShouldEqual, "member@example.com")
+			So(foundInvitation.Pending(time.Now()), ShouldBeTrue)
+		})
+
+		Convey("keeps workspace todos out of personal listings", func() {
+
+			todo, err := CreateTodo(ctx, dB, owner)
+			So(err, ShouldBeNil)
+
+			workspaceTodo := entities.BuildTodo(owner)
+			workspaceTodo.WorkspaceID = &workspace.ID
+
+			err = todoRepository.Save(ctx, dB, workspaceTodo)
+			So(err, ShouldBeNil)
+
+			todos, err := todoRepository.Todos(ctx, dB, &forms.Filter{OwnerID: owner.ID})
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 1)
+			So(todos[0].ID, ShouldEqual, todo.ID)
+
+			todos, err = todoRepository.Todos(ctx, dB, &forms.Filter{OwnerID: owner.ID, WorkspaceID: workspace.ID})
+			So(err, ShouldBeNil)
+
+			So(len(todos), ShouldEqual, 1)
+			So(todos[0].ID, ShouldEqual, workspaceTodo.ID)
+		})
+	}))
+}
@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/forms"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// CursorCodec turns keyset positions into opaque tokens. The payload is
+// signed so clients cannot forge positions or inject sort values.
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret string) *CursorCodec {
+
+	if secret == "" {
+		panic("cursor secret is empty")
+	}
+
+	return &CursorCodec{
+		secret: []byte(secret),
+	}
+}
+
+func (c *CursorCodec) Encode(cursor *forms.Cursor) (string, error) {
+
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	signature := base64.RawURLEncoding.EncodeToString(c.sign(encodedPayload))
+
+	return encodedPayload + "." + signature, nil
+}
+
+func (c *CursorCodec) Decode(token string) (*forms.Cursor, error) {
+
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return &forms.Cursor{}, errInvalidCursor
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return &forms.Cursor{}, errInvalidCursor
+	}
+
+	if !hmac.Equal(signature, c.sign(encodedPayload)) {
+		return &forms.Cursor{}, errInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return &forms.Cursor{}, errInvalidCursor
+	}
+
+	var cursor forms.Cursor
+
+	err = json.Unmarshal(payload, &cursor)
+	if err != nil {
+		return &forms.Cursor{}, errInvalidCursor
+	}
+
+	return &cursor, nil
+}
+
+func (c *CursorCodec) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/forms"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCursorCodec(t *testing.T) {
+
+	Convey("TestCursorCodec", t, func() {
+
+		cursorCodec := NewCursorCodec("secret")
+
+		cursor := &forms.Cursor{
+			Backward: true,
+			ID:       42,
+			Sort:     "-created_at",
+			Values:   []string{"2024-08-16T17:22:01.123456Z"},
+		}
+
+		Convey("can encode and decode a cursor", func() {
+
+			token, err := cursorCodec.Encode(cursor)
+			So(err, ShouldBeNil)
+
+			decodedCursor, err := cursorCodec.Decode(token)
+			So(err, ShouldBeNil)
+
+			So(decodedCursor, ShouldResemble, cursor)
+		})
+
+		Convey("cannot decode a tampered cursor", func() {
+
+			token, err := cursorCodec.Encode(cursor)
+			So(err, ShouldBeNil)
+
+			otherToken, err := cursorCodec.Encode(&forms.Cursor{ID: 1})
+			So(err, ShouldBeNil)
+
+			payload, _, _ := strings.Cut(otherToken, ".")
+			_, signature, _ := strings.Cut(token, ".")
+
+			_, err = cursorCodec.Decode(payload + "." + signature)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("cannot decode a cursor signed with another secret", func() {
+
+			token, err := NewCursorCodec("other-secret").Encode(cursor)
+			So(err, ShouldBeNil)
+
+			_, err = cursorCodec.Decode(token)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("cannot decode garbage", func() {
+
+			_, err := cursorCodec.Decode("not-a-cursor")
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
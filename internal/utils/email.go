@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+func ValidateEmail(email string) error {
+
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return fmt.Errorf("email cannot be empty")
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return fmt.Errorf("invalid email address")
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package utils
+
+import "strings"
+
+// MatchETag reports whether an If-Match header value matches etag. The value
+// may be "*" or a list of entity tags. Weak tags never match because If-Match
+// uses the strong comparison.
+func MatchETag(ifMatch, etag string) bool {
+
+	for _, candidate := range strings.Split(ifMatch, ",") {
+
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMatchETag(t *testing.T) {
+
+	Convey("TestMatchETag", t, func() {
+
+		Convey("matches the same tag, a list containing it or any tag", func() {
+
+			So(MatchETag(`"3"`, `"3"`), ShouldBeTrue)
+			So(MatchETag(`"2", "3"`, `"3"`), ShouldBeTrue)
+			So(MatchETag(`*`, `"3"`), ShouldBeTrue)
+		})
+
+		Convey("does not match other or weak tags", func() {
+
+			So(MatchETag(`"2"`, `"3"`), ShouldBeFalse)
+			So(MatchETag(`W/"3"`, `"3"`), ShouldBeFalse)
+			So(MatchETag(`3`, `"3"`), ShouldBeFalse)
+		})
+	})
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const minPasswordLength = 8
+
+func CheckPassword(passwordHash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) == nil
+}
+
+func HashPassword(password string) (string, error) {
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func ValidatePassword(password string) error {
+
+	if len(password) < minPasswordLength {
+		return fmt.Errorf("password must be at least %v characters", minPasswordLength)
+	}
+
+	if len(password) > 72 {
+		return fmt.Errorf("password length exceed 72 characters")
+	}
+
+	return nil
+}
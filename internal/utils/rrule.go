@@ -0,0 +1,245 @@
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+
+	rruleDateLayout     = "20060102"
+	rruleDateTimeLayout = "20060102T150405Z"
+
+	// bounds the search for the next occurrence, a rule such as the 31st of
+	// every 12th month can go years without matching
+	maxRecurrenceSearchDays = 366 * 12
+)
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// RecurrenceRule is the subset of an RFC 5545 RRULE supported for todos:
+// FREQ (DAILY, WEEKLY or MONTHLY), INTERVAL, BYDAY without ordinals, UNTIL
+// and COUNT.
+type RecurrenceRule struct {
+	ByDay     []time.Weekday
+	Count     int
+	Frequency string
+	Interval  int
+	Until     *time.Time
+}
+
+func ParseRecurrenceRule(value string) (*RecurrenceRule, error) {
+
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return &RecurrenceRule{}, fmt.Errorf("recurrence rule cannot be empty")
+	}
+
+	rule := &RecurrenceRule{
+		Interval: 1,
+	}
+
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+
+		key, partValue, found := strings.Cut(part, "=")
+		if !found || partValue == "" {
+			return &RecurrenceRule{}, fmt.Errorf("invalid recurrence rule part %v", part)
+		}
+
+		key = strings.ToUpper(key)
+
+		if seen[key] {
+			return &RecurrenceRule{}, fmt.Errorf("duplicate recurrence rule part %v", key)
+		}
+
+		seen[key] = true
+
+		var err error
+
+		switch key {
+		case "FREQ":
+			rule.Frequency = strings.ToUpper(partValue)
+			if rule.Frequency != FrequencyDaily && rule.Frequency != FrequencyWeekly && rule.Frequency != FrequencyMonthly {
+				return &RecurrenceRule{}, fmt.Errorf("unsupported recurrence frequency %v", partValue)
+			}
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(partValue)
+			if err != nil || rule.Interval < 1 {
+				return &RecurrenceRule{}, fmt.Errorf("recurrence interval must be a positive number")
+			}
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(partValue)
+			if err != nil || rule.Count < 1 {
+				return &RecurrenceRule{}, fmt.Errorf("recurrence count must be a positive number")
+			}
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(partValue)
+			if err != nil {
+				return &RecurrenceRule{}, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			rule.ByDay, err = parseRecurrenceByDay(partValue)
+			if err != nil {
+				return &RecurrenceRule{}, err
+			}
+		default:
+			return &RecurrenceRule{}, fmt.Errorf("unsupported recurrence rule part %v", key)
+		}
+	}
+
+	if rule.Frequency == "" {
+		return &RecurrenceRule{}, fmt.Errorf("recurrence rule must have a frequency")
+	}
+
+	if rule.Count > 0 && rule.Until != nil {
+		return &RecurrenceRule{}, fmt.Errorf("recurrence rule cannot have both count and until")
+	}
+
+	return rule, nil
+}
+
+// Next returns the first occurrence after previous, which is itself an
+// occurrence of a series that started at start. occurrence is the 1-based
+// position of previous in the series and is checked against COUNT.
+func (r *RecurrenceRule) Next(start, previous time.Time, occurrence int) (time.Time, bool) {
+
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	candidate := previous
+
+	for i := 0; i < maxRecurrenceSearchDays; i++ {
+
+		candidate = candidate.AddDate(0, 0, 1)
+
+		if r.Until != nil && candidate.After(*r.Until) {
+			return time.Time{}, false
+		}
+
+		if r.matches(start, candidate) {
+			return candidate, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (r *RecurrenceRule) String() string {
+
+	parts := []string{"FREQ=" + r.Frequency}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			days = append(days, weekdayCodes[day])
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(rruleDateTimeLayout))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (r *RecurrenceRule) matches(start, candidate time.Time) bool {
+
+	startYear, startMonth, startDay := start.Date()
+	year, month, day := candidate.Date()
+
+	startDate := time.Date(startYear, startMonth, startDay, 0, 0, 0, 0, time.UTC)
+	date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+	var period int
+
+	switch r.Frequency {
+	case FrequencyDaily:
+		period = int(date.Sub(startDate).Hours() / 24)
+	case FrequencyWeekly:
+		// weeks start on Monday, the RFC 5545 default
+		period = int(startOfWeek(date).Sub(startOfWeek(startDate)).Hours() / (24 * 7))
+	case FrequencyMonthly:
+		period = (year-startYear)*12 + int(month-startMonth)
+	}
+
+	if period%r.Interval != 0 {
+		return false
+	}
+
+	if len(r.ByDay) > 0 {
+		return slices.Contains(r.ByDay, candidate.Weekday())
+	}
+
+	switch r.Frequency {
+	case FrequencyWeekly:
+		return candidate.Weekday() == start.Weekday()
+	case FrequencyMonthly:
+		// months without the start day are skipped, as in RFC 5545
+		return day == startDay
+	}
+
+	return true
+}
+
+func startOfWeek(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+func parseRecurrenceByDay(value string) ([]time.Weekday, error) {
+
+	days := make([]time.Weekday, 0)
+
+	for _, code := range strings.Split(strings.ToUpper(value), ",") {
+
+		index := slices.Index(weekdayCodes, strings.TrimSpace(code))
+		if index < 0 {
+			return []time.Weekday{}, fmt.Errorf("unsupported recurrence day %v", code)
+		}
+
+		if !slices.Contains(days, time.Weekday(index)) {
+			days = append(days, time.Weekday(index))
+		}
+	}
+
+	slices.Sort(days)
+
+	return days, nil
+}
+
+func parseRecurrenceUntil(value string) (time.Time, error) {
+
+	until, err := time.Parse(rruleDateTimeLayout, value)
+	if err == nil {
+		return until, nil
+	}
+
+	until, err = time.Parse(rruleDateLayout, value)
+	if err == nil {
+		// a date includes the whole day
+		return until.Add(24*time.Hour - time.Second), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid recurrence until %v, expected YYYYMMDD or YYYYMMDDTHHMMSSZ", value)
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestRecurrenceRule(t *testing.T) {
+
+	Convey("TestRecurrenceRule", t, func() {
+
+		// a Monday
+		start := time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)
+
+		Convey("can parse and print a rule", func() {
+
+			rule, err := ParseRecurrenceRule("RRULE:freq=weekly;INTERVAL=2;BYDAY=FR,MO;COUNT=4")
+			So(err, ShouldBeNil)
+
+			So(rule.Frequency, ShouldEqual, FrequencyWeekly)
+			So(rule.Interval, ShouldEqual, 2)
+			So(rule.ByDay, ShouldResemble, []time.Weekday{time.Monday, time.Friday})
+			So(rule.Count, ShouldEqual, 4)
+			So(rule.String(), ShouldEqual, "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=4")
+		})
+
+		Convey("rejects unsupported rules", func() {
+
+			for _, value := range []string{
+				"",
+				"INTERVAL=2",
+				"FREQ=YEARLY",
+				"FREQ=DAILY;INTERVAL=0",
+				"FREQ=WEEKLY;BYDAY=1MO",
+				"FREQ=DAILY;COUNT=2;UNTIL=20241231",
+				"FREQ=DAILY;BYHOUR=9",
+				"FREQ=DAILY;FREQ=WEEKLY",
+			} {
+				_, err := ParseRecurrenceRule(value)
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+		Convey("computes daily occurrences", func() {
+
+			rule, err := ParseRecurrenceRule("FREQ=DAILY;INTERVAL=3")
+			So(err, ShouldBeNil)
+
+			next, ok := rule.Next(start, start, 1)
+			So(ok, ShouldBeTrue)
+			So(next, ShouldEqual, start.AddDate(0, 0, 3))
+		})
+
+		Convey("computes weekly occurrences on several days", func() {
+
+			rule, err := ParseRecurrenceRule("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR")
+			So(err, ShouldBeNil)
+
+			next, ok := rule.Next(start, start, 1)
+			So(ok, ShouldBeTrue)
+			So(next, ShouldEqual, time.Date(2024, 9, 6, 9, 0, 0, 0, time.UTC))
+
+			next, ok = rule.Next(start, next, 2)
+			So(ok, ShouldBeTrue)
+			So(next, ShouldEqual, time.Date(2024, 9, 16, 9, 0, 0, 0, time.UTC))
+		})
+
+		Convey("skips months without the start day", func() {
+
+			monthEnd := time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC)
+
+			rule, err := ParseRecurrenceRule("FREQ=MONTHLY")
+			So(err, ShouldBeNil)
+
+			next, ok := rule.Next(monthEnd, monthEnd, 1)
+			So(ok, ShouldBeTrue)
+			So(next, ShouldEqual, time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC))
+		})
+
+		Convey("stops after count occurrences", func() {
+
+			rule, err := ParseRecurrenceRule("FREQ=DAILY;COUNT=2")
+			So(err, ShouldBeNil)
+
+			_, ok := rule.Next(start, start, 1)
+			So(ok, ShouldBeTrue)
+
+			_, ok = rule.Next(start, start.AddDate(0, 0, 1), 2)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("stops after until", func() {
+
+			rule, err := ParseRecurrenceRule("FREQ=WEEKLY;UNTIL=20240909")
+			So(err, ShouldBeNil)
+
+			next, ok := rule.Next(start, start, 1)
+			So(ok, ShouldBeTrue)
+			So(next, ShouldEqual, time.Date(2024, 9, 9, 9, 0, 0, 0, time.UTC))
+
+			_, ok = rule.Next(start, next, 2)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("keeps the local time of day across daylight saving changes", func() {
+
+			location, err := time.LoadLocation("Europe/Berlin")
+			So(err, ShouldBeNil)
+
+			beforeChange := time.Date(2024, 10, 26, 9, 0, 0, 0, location)
+
+			rule, err := ParseRecurrenceRule("FREQ=DAILY")
+			So(err, ShouldBeNil)
+
+			next, ok := rule.Next(beforeChange, beforeChange, 1)
+			So(ok, ShouldBeTrue)
+			So(next.Hour(), ShouldEqual, 9)
+			So(next.Sub(beforeChange), ShouldEqual, 25*time.Hour)
+		})
+	})
+}
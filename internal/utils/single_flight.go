@@ -0,0 +1,51 @@
+package utils
+
+import "sync"
+
+type (
+	// SingleFlight runs at most one call per key at a time. Callers that
+	// arrive while a call for their key is running wait for it and share its
+	// result instead of starting their own.
+	SingleFlight[T any] struct {
+		mu    sync.Mutex
+		calls map[string]*flightCall[T]
+	}
+
+	flightCall[T any] struct {
+		done  chan struct{}
+		value T
+		err   error
+	}
+)
+
+func (g *SingleFlight[T]) Do(key string, fn func() (T, error)) (T, error) {
+
+	g.mu.Lock()
+
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.value, call.err
+	}
+
+	call := &flightCall[T]{done: make(chan struct{})}
+	g.calls[key] = call
+
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	call.value, call.err = fn()
+
+	return call.value, call.err
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestSingleFlight(t *testing.T) {
+
+	Convey("TestSingleFlight", t, func() {
+
+		var group SingleFlight[int]
+
+		Convey("shares one call between concurrent callers of a key", func() {
+
+			var calls atomic.Int32
+
+			release := make(chan struct{})
+			started := make(chan struct{})
+
+			var wg sync.WaitGroup
+
+			results := make([]int, 5)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[0], _ = group.Do("key", func() (int, error) {
+					close(started)
+					<-release
+					return int(calls.Add(1)), nil
+				})
+			}()
+
+			<-started
+
+			for i := 1; i < len(results); i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					results[i], _ = group.Do("key", func() (int, error) {
+						return int(calls.Add(1)), nil
+					})
+				}(i)
+			}
+
+			// gives the other callers time to join the running call
+			time.Sleep(50 * time.Millisecond)
+
+			close(release)
+			wg.Wait()
+
+			So(calls.Load(), ShouldEqual, 1)
+			So(results, ShouldResemble, []int{1, 1, 1, 1, 1})
+		})
+
+		Convey("runs a new call once the previous one has finished", func() {
+
+			value, err := group.Do("key", func() (int, error) { return 1, nil })
+			So(err, ShouldBeNil)
+			So(value, ShouldEqual, 1)
+
+			_, err = group.Do("key", func() (int, error) { return 0, errors.New("failed") })
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
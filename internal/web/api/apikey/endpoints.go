@@ -0,0 +1,18 @@
+package apikey
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AddAuthenticatedEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	apiKeyController controller.APIKeyController,
+) {
+	r.POST("/api-keys", middleware.RequireWriteScope(), createAPIKey(dB, apiKeyController))
+	r.GET("/api-keys", listAPIKeys(dB, apiKeyController))
+	r.DELETE("/api-keys/:id", middleware.RequireWriteScope(), revokeAPIKey(dB, apiKeyController))
+}
@@ -0,0 +1,80 @@
+package apikey
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+func createAPIKey(
+	dB db.DB,
+	apiKeyController controller.APIKeyController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.CreateAPIKeyForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		apiKey, err := apiKeyController.CreateAPIKey(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusCreated, apiKey)
+	}
+}
+
+func listAPIKeys(
+	dB db.DB,
+	apiKeyController controller.APIKeyController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		apiKeys, err := apiKeyController.APIKeys(c.Request.Context(), dB)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"api_keys": apiKeys})
+	}
+}
+
+func revokeAPIKey(
+	dB db.DB,
+	apiKeyController controller.APIKeyController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		apiKeyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		err = apiKeyController.RevokeAPIKey(c.Request.Context(), dB, apiKeyID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
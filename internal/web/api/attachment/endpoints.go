@@ -0,0 +1,19 @@
+package attachment
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AddAuthenticatedEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	attachmentController controller.AttachmentController,
+) {
+	r.POST("/todo/:id/attachments", middleware.RequireWriteScope(), createAttachment(dB, attachmentController))
+	r.GET("/todo/:id/attachments", listAttachments(dB, attachmentController))
+	r.GET("/todo/:id/attachments/:attachment_id", downloadAttachment(dB, attachmentController))
+	r.DELETE("/todo/:id/attachments/:attachment_id", middleware.RequireWriteScope(), deleteAttachment(dB, attachmentController))
+}
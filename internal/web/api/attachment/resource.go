@@ -0,0 +1,178 @@
+package attachment
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead leaves room for the part headers and boundaries around
+// the largest allowed file.
+const multipartOverhead = 1 << 20
+
+func createAttachment(
+	dB db.DB,
+	attachmentController controller.AttachmentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, controller.MaxAttachmentSize+multipartOverhead)
+
+		reader, err := c.Request.MultipartReader()
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		// the file is streamed from the first part named file rather than
+		// buffered by ParseMultipartForm
+		for {
+			part, err := reader.NextPart()
+			if err != nil {
+				appError := apperror.Wrap(uploadError(err))
+				webutils.HandleError(c, appError)
+				return
+			}
+
+			if part.FormName() != "file" {
+				part.Close()
+				continue
+			}
+
+			attachment, err := attachmentController.CreateAttachment(c.Request.Context(), dB, todoID, part.FileName(), part)
+			part.Close()
+			if err != nil {
+				appError := apperror.Wrap(uploadError(err))
+				webutils.HandleError(c, appError)
+				return
+			}
+
+			c.JSON(http.StatusCreated, attachment)
+			return
+		}
+	}
+}
+
+func listAttachments(
+	dB db.DB,
+	attachmentController controller.AttachmentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		attachments, err := attachmentController.Attachments(c.Request.Context(), dB, todoID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"attachments": attachments})
+	}
+}
+
+func downloadAttachment(
+	dB db.DB,
+	attachmentController controller.AttachmentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		attachmentID, err := strconv.ParseInt(c.Param("attachment_id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		attachment, content, err := attachmentController.AttachmentContent(c.Request.Context(), dB, todoID, attachmentID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		defer content.Close()
+
+		headers := map[string]string{
+			"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}),
+			"X-Content-Type-Options": "nosniff",
+		}
+
+		c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, headers)
+	}
+}
+
+func deleteAttachment(
+	dB db.DB,
+	attachmentController controller.AttachmentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		attachmentID, err := strconv.ParseInt(c.Param("attachment_id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		err = attachmentController.DeleteAttachment(c.Request.Context(), dB, todoID, attachmentID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
+
+func uploadError(err error) error {
+
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return apperror.Wrap(fmt.Errorf("attachment cannot be larger than %v MB", controller.MaxAttachmentSize>>20)).SetHttpStatusCode(http.StatusRequestEntityTooLarge)
+	}
+
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("file is required")
+	}
+
+	return err
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/gin-gonic/gin"
+)
+
+func AddOpenEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	authController controller.AuthController,
+) {
+	r.POST("/auth/signup", signup(dB, authController))
+	r.POST("/auth/login", login(dB, authController))
+	r.POST("/auth/refresh", refreshToken(dB, authController))
+	r.POST("/auth/logout", logout(dB, authController))
+}
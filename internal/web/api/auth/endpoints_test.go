@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/providers"
+	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+	"syreclabs.com/go/faker"
+)
+
+func TestAuthEndpoints(t *testing.T) {
+
+	testDB := db.InitDB()
+	defer testDB.Close()
+
+	ctx := context.Background()
+
+	authController := controller.NewTestAuthController(providers.NewJWTWithSecret("test-secret", nil))
+
+	Convey("TestAuthEndpoints", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
+
+		testRouter := gin.Default()
+		testRouter.Use(middleware.DefaultMiddlewares()...)
+
+		routerGroup := testRouter.Group("")
+
+		AddOpenEndpoints(routerGroup, dB, authController)
+
+		signupForm := &forms.SignupForm{
+			Email:    faker.Internet().Email(),
+			Password: "password123",
+		}
+
+		Convey("can sign up", func() {
+
+			w, err := testutils.DoRequest(testRouter, http.MethodPost, "/auth/signup", signupForm)
+			So(err, ShouldBeNil)
+
+			So(w.Code, ShouldEqual, http.StatusCreated)
+
+			var authTokens entities.AuthTokens
+
+			data, err := io.ReadAll(w.Body)
+			So(err, ShouldBeNil)
+
+			err = json.Unmarshal(data, &authTokens)
+			So(err, ShouldBeNil)
+
+			So(authTokens.AccessToken, ShouldNotBeEmpty)
+			So(authTokens.RefreshToken, ShouldNotBeEmpty)
+			So(string(data), ShouldNotContainSubstring, "password")
+		})
+
+		Convey("can login", func() {
+
+			_, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			form := &forms.LoginForm{
+				Email:    signupForm.Email,
+				Password: signupForm.Password,
+			}
+
+			w, err := testutils.DoRequest(testRouter, http.MethodPost, "/auth/login", form)
+			So(err, ShouldBeNil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("cannot login with invalid credentials", func() {
+
+			form := &forms.LoginForm{
+				Email:    signupForm.Email,
+				Password: signupForm.Password,
+			}
+
+			w, err := testutils.DoRequest(testRouter, http.MethodPost, "/auth/login", form)
+			So(err, ShouldBeNil)
+
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("can refresh a token", func() {
+
+			authTokens, err := authController.Signup(ctx, dB, signupForm)
+			So(err, ShouldBeNil)
+
+			form := &forms.RefreshTokenForm{
+				RefreshToken: authTokens.RefreshToken,
+			}
+
+			w, err := testutils.DoRequest(testRouter, http.MethodPost, "/auth/refresh", form)
+			So(err, ShouldBeNil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+	}))
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+func signup(
+	dB db.DB,
+	authController controller.AuthController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.SignupForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		authTokens, err := authController.Signup(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusCreated, authTokens)
+	}
+}
+
+func login(
+	dB db.DB,
+	authController controller.AuthController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.LoginForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		authTokens, err := authController.Login(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, authTokens)
+	}
+}
+
+func refreshToken(
+	dB db.DB,
+	authController controller.AuthController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.RefreshTokenForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		authTokens, err := authController.RefreshToken(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, authTokens)
+	}
+}
+
+func logout(
+	dB db.DB,
+	authController controller.AuthController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.RefreshTokenForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		err = authController.Logout(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
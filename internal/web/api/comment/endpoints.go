@@ -0,0 +1,19 @@
+package comment
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AddAuthenticatedEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	commentController controller.CommentController,
+) {
+	r.GET("/todo/:id/comments", listComments(dB, commentController))
+	r.POST("/todo/:id/comments", middleware.RequireWriteScope(), createComment(dB, commentController))
+	r.PUT("/comments/:id", middleware.RequireWriteScope(), updateComment(dB, commentController))
+	r.DELETE("/comments/:id", middleware.RequireWriteScope(), deleteComment(dB, commentController))
+}
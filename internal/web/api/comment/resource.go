@@ -0,0 +1,134 @@
+package comment
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+func listComments(
+	dB db.DB,
+	commentController controller.CommentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		page, per, err := webutils.PaginationFromContext(c)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		comments, err := commentController.Comments(c.Request.Context(), dB, todoID, page, per)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, comments)
+	}
+}
+
+func createComment(
+	dB db.DB,
+	commentController controller.CommentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.CreateCommentForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		comment, err := commentController.CreateComment(c.Request.Context(), dB, todoID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusCreated, comment)
+	}
+}
+
+func updateComment(
+	dB db.DB,
+	commentController controller.CommentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.UpdateCommentForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		comment, err := commentController.UpdateComment(c.Request.Context(), dB, commentID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, comment)
+	}
+}
+
+func deleteComment(
+	dB db.DB,
+	commentController controller.CommentController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		commentID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		err = commentController.DeleteComment(c.Request.Context(), dB, commentID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
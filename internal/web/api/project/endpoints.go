@@ -0,0 +1,21 @@
+package project
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AddAuthenticatedEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	projectController controller.ProjectController,
+) {
+	r.POST("/project", middleware.RequireWriteScope(), createProject(dB, projectController))
+	r.GET("/projects", listProjects(dB, projectController))
+	r.GET("/projects/:id/todos", listProjectTodos(dB, projectController))
+	r.GET("/project/:id", projectByID(dB, projectController))
+	r.PUT("/project/:id", middleware.RequireWriteScope(), updateProject(dB, projectController))
+	r.DELETE("/project/:id", middleware.RequireWriteScope(), deleteProject(dB, projectController))
+}
@@ -0,0 +1,185 @@
+package project
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+func createProject(
+	dB db.DB,
+	projectController controller.ProjectController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.CreateProjectForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		project, err := projectController.CreateProject(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusCreated, project)
+	}
+}
+
+func listProjects(
+	dB db.DB,
+	projectController controller.ProjectController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		includeArchived := false
+
+		includeArchivedQuery := strings.TrimSpace(c.Query("include_archived"))
+		if includeArchivedQuery != "" {
+
+			var err error
+
+			includeArchived, err = strconv.ParseBool(includeArchivedQuery)
+			if err != nil {
+				appError := apperror.Wrap(fmt.Errorf("invalid include_archived argument %v", err))
+				webutils.HandleError(c, appError)
+				return
+			}
+		}
+
+		projects, err := projectController.Projects(c.Request.Context(), dB, includeArchived)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"projects": projects})
+	}
+}
+
+func listProjectTodos(
+	dB db.DB,
+	projectController controller.ProjectController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		filter, err := webutils.FilterFromContext(c)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todos, err := projectController.ProjectTodos(c.Request.Context(), dB, projectID, filter)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, todos)
+	}
+}
+
+func projectByID(
+	dB db.DB,
+	projectController controller.ProjectController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		project, err := projectController.ProjectByID(c.Request.Context(), dB, projectID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, project)
+	}
+}
+
+func updateProject(
+	dB db.DB,
+	projectController controller.ProjectController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.UpdateProjectForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		project, err := projectController.UpdateProject(c.Request.Context(), dB, projectID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, project)
+	}
+}
+
+func deleteProject(
+	dB db.DB,
+	projectController controller.ProjectController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		projectID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		err = projectController.DeleteProject(c.Request.Context(), dB, projectID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
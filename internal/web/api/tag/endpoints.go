@@ -0,0 +1,20 @@
+package tag
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AddAuthenticatedEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	tagController controller.TagController,
+) {
+	r.POST("/tag", middleware.RequireWriteScope(), createTag(dB, tagController))
+	r.GET("/tags", listTags(dB, tagController))
+	r.GET("/tag/:id", tagByID(dB, tagController))
+	r.PUT("/tag/:id", middleware.RequireWriteScope(), updateTag(dB, tagController))
+	r.DELETE("/tag/:id", middleware.RequireWriteScope(), deleteTag(dB, tagController))
+}
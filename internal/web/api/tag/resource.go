@@ -0,0 +1,137 @@
+package tag
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+func createTag(
+	dB db.DB,
+	tagController controller.TagController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.CreateTagForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		tag, err := tagController.CreateTag(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusCreated, tag)
+	}
+}
+
+func listTags(
+	dB db.DB,
+	tagController controller.TagController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		tags, err := tagController.Tags(c.Request.Context(), dB)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"tags": tags})
+	}
+}
+
+func tagByID(
+	dB db.DB,
+	tagController controller.TagController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		tagID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		tag, err := tagController.TagByID(c.Request.Context(), dB, tagID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, tag)
+	}
+}
+
+func updateTag(
+	dB db.DB,
+	tagController controller.TagController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		tagID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.UpdateTagForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		tag, err := tagController.UpdateTag(c.Request.Context(), dB, tagID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, tag)
+	}
+}
+
+func deleteTag(
+	dB db.DB,
+	tagController controller.TagController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		tagID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		err = tagController.DeleteTag(c.Request.Context(), dB, tagID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
@@ -3,18 +3,28 @@ package todo
 import (
 	"github.com/ernestngugi/todo/internal/controller"
 	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
 	"github.com/gin-gonic/gin"
 )
 
-func AddOpenEndpoints(
+func AddAuthenticatedEndpoints(
 	r *gin.RouterGroup,
 	dB db.DB,
 	todoController controller.TodoController,
 ) {
-	r.POST("/todo", createTodo(dB, todoController))
+	r.POST("/todo", middleware.RequireWriteScope(), createTodo(dB, todoController))
 	r.GET("/todos", listTodo(dB, todoController))
+	r.GET("/todos/today", listTodosDueToday(dB, todoController))
+	r.GET("/todos/assigned", listTodosAssignedToMe(dB, todoController))
 	r.GET("/todo/:id", todoByID(dB, todoController))
-	r.PUT("/todo/:id", updateTodo(dB, todoController))
-	r.POST("/todo/:id", completeTodo(dB, todoController))
-	r.DELETE("/todo/:id", deleteTodo(dB, todoController))
+	r.PUT("/todo/:id", middleware.RequireWriteScope(), updateTodo(dB, todoController))
+	r.POST("/todo/:id", middleware.RequireWriteScope(), completeTodo(dB, todoController))
+	r.DELETE("/todo/:id", middleware.RequireWriteScope(), deleteTodo(dB, todoController))
+	r.POST("/todo/:id/transitions", middleware.RequireWriteScope(), transitionTodo(dB, todoController))
+	r.PUT("/todo/:id/assignee", middleware.RequireWriteScope(), assignTodo(dB, todoController))
+	r.DELETE("/todo/:id/assignee", middleware.RequireWriteScope(), unassignTodo(dB, todoController))
+	r.GET("/todo/:id/activity", listTodoActivities(dB, todoController))
+	r.GET("/todo/:id/history", listTodoHistory(dB, todoController))
+	r.POST("/todo/:id/restore", middleware.RequireWriteScope(), restoreTodo(dB, todoController))
+	r.GET("/trash", listTrash(dB, todoController))
 }
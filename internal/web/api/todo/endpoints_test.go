@@ -15,6 +15,7 @@ import (
 	"github.com/ernestngugi/todo/internal/mocks"
 	"github.com/ernestngugi/todo/internal/repository"
 	"github.com/ernestngugi/todo/internal/testutils"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
 	"github.com/ernestngugi/todo/internal/web/middleware"
 	"github.com/gin-gonic/gin"
 	. "github.com/smartystreets/goconvey/convey"
@@ -34,16 +35,25 @@ func TestTodoEndpoints(t *testing.T) {
 
 	Convey("TestTodoEndpoints", t, testutils.WithTestDB(ctx, testDB, func(ctx context.Context, dB db.DB) {
 
+		user, err := repository.CreateUser(ctx, dB)
+		So(err, ShouldBeNil)
+
+		ctx = contexthelper.WithUser(ctx, user)
+
 		testRouter := gin.Default()
 		testRouter.Use(middleware.DefaultMiddlewares()...)
+		testRouter.Use(func(c *gin.Context) {
+			c.Request = c.Request.WithContext(contexthelper.WithUser(c.Request.Context(), user))
+			c.Next()
+		})
 
 		routerGroup := testRouter.Group("")
 
-		AddOpenEndpoints(routerGroup, dB, todoController)
+		AddAuthenticatedEndpoints(routerGroup, dB, todoController)
 
 		Convey("can get todo by id", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			w, err := testutils.DoRequest(testRouter, http.MethodGet, fmt.Sprintf("/todo/%v", todo.ID), nil)
@@ -70,7 +80,7 @@ func TestTodoEndpoints(t *testing.T) {
 
 		Convey("can mark a todo as complete", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			w, err := testutils.DoRequest(testRouter, http.MethodPost, fmt.Sprintf("/todo/%v", todo.ID), nil)
@@ -97,7 +107,7 @@ func TestTodoEndpoints(t *testing.T) {
 
 		Convey("can delete todo by id", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			w, err := testutils.DoRequest(testRouter, http.MethodDelete, fmt.Sprintf("/todo/%v", todo.ID), nil)
@@ -142,7 +152,7 @@ func TestTodoEndpoints(t *testing.T) {
 
 		Convey("can update a todo", func() {
 
-			todo, err := repository.CreateTodo(ctx, dB)
+			todo, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			newTitle := "newtitle"
@@ -175,10 +185,10 @@ func TestTodoEndpoints(t *testing.T) {
 
 		Convey("can get a list of todos", func() {
 
-			todo1, err := repository.CreateTodo(ctx, dB)
+			todo1, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
-			todo2, err := repository.CreateTodo(ctx, dB)
+			todo2, err := repository.CreateTodo(ctx, dB, user)
 			So(err, ShouldBeNil)
 
 			w, err := testutils.DoRequest(testRouter, http.MethodGet, "/todos?page=1&per=20", nil)
@@ -215,5 +225,68 @@ func TestTodoEndpoints(t *testing.T) {
 
 			So(todoList.Pagination.Count, ShouldEqual, 2)
 		})
+
+		Convey("can filter the list of todos", func() {
+
+			_, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			todo, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			_, err = todoController.CompleteTodo(ctx, dB, todo.ID, &forms.CompleteTodoForm{})
+			So(err, ShouldBeNil)
+
+			w, err := testutils.DoRequest(testRouter, http.MethodGet, "/todos?completed=true", nil)
+			So(err, ShouldBeNil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var todoList entities.TodoList
+
+			data, err := io.ReadAll(w.Body)
+			So(err, ShouldBeNil)
+
+			err = json.Unmarshal(data, &todoList)
+			So(err, ShouldBeNil)
+
+			So(len(todoList.Todos), ShouldEqual, 1)
+			So(todoList.Todos[0].ID, ShouldEqual, todo.ID)
+			So(todoList.Pagination.Count, ShouldEqual, 1)
+		})
+
+		Convey("cannot filter the list of todos with an invalid argument", func() {
+
+			w, err := testutils.DoRequest(testRouter, http.MethodGet, "/todos?completed=maybe", nil)
+			So(err, ShouldBeNil)
+
+			So(w.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("can sort the list of todos", func() {
+
+			todo1, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			todo2, err := repository.CreateTodo(ctx, dB, user)
+			So(err, ShouldBeNil)
+
+			w, err := testutils.DoRequest(testRouter, http.MethodGet, "/todos?sort=-created_at", nil)
+			So(err, ShouldBeNil)
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+
+			var todoList entities.TodoList
+
+			data, err := io.ReadAll(w.Body)
+			So(err, ShouldBeNil)
+
+			err = json.Unmarshal(data, &todoList)
+			So(err, ShouldBeNil)
+
+			So(len(todoList.Todos), ShouldEqual, 2)
+			So(todoList.Todos[0].ID, ShouldEqual, todo2.ID)
+			So(todoList.Todos[1].ID, ShouldEqual, todo1.ID)
+		})
 	}))
 }
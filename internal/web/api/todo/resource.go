@@ -3,6 +3,7 @@ package todo
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/ernestngugi/todo/internal/apperror"
 	"github.com/ernestngugi/todo/internal/controller"
@@ -34,6 +35,7 @@ func createTodo(
 			return
 		}
 
+		c.Header("ETag", todo.ETag())
 		c.JSON(http.StatusOK, todo)
 	}
 }
@@ -51,13 +53,18 @@ func completeTodo(
 			return
 		}
 
-		todo, err := todoController.CompleteTodo(c.Request.Context(), dB, todoID)
+		form := &forms.CompleteTodoForm{
+			OpenItems: strings.TrimSpace(c.Query("open_items")),
+		}
+
+		todo, err := todoController.CompleteTodo(c.Request.Context(), dB, todoID, form)
 		if err != nil {
 			appError := apperror.Wrap(err)
 			webutils.HandleError(c, appError)
 			return
 		}
 
+		c.Header("ETag", todo.ETag())
 		c.JSON(http.StatusOK, todo)
 	}
 }
@@ -91,6 +98,41 @@ func updateTodo(
 			return
 		}
 
+		c.Header("ETag", todo.ETag())
+		c.JSON(http.StatusOK, todo)
+	}
+}
+
+func transitionTodo(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.TransitionTodoForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, err := todoController.TransitionTodo(c.Request.Context(), dB, todoID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
 		c.JSON(http.StatusOK, todo)
 	}
 }
@@ -115,6 +157,7 @@ func todoByID(
 			return
 		}
 
+		c.Header("ETag", todo.ETag())
 		c.JSON(http.StatusOK, todo)
 	}
 }
@@ -166,3 +209,214 @@ func listTodo(
 		c.JSON(http.StatusOK, todos)
 	}
 }
+
+func listTodosDueToday(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		location, err := webutils.LocationFromContext(c)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		filter, err := webutils.FilterFromContext(c)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todos, err := todoController.TodosDueToday(c.Request.Context(), dB, location, filter)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, todos)
+	}
+}
+
+func listTodosAssignedToMe(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		filter, err := webutils.FilterFromContext(c)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todos, err := todoController.TodosAssignedToMe(c.Request.Context(), dB, filter)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, todos)
+	}
+}
+
+func assignTodo(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.AssignTodoForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, err := todoController.AssignTodo(c.Request.Context(), dB, todoID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
+		c.JSON(http.StatusOK, todo)
+	}
+}
+
+func unassignTodo(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, err := todoController.UnassignTodo(c.Request.Context(), dB, todoID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
+		c.JSON(http.StatusOK, todo)
+	}
+}
+
+func listTodoActivities(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		activities, err := todoController.TodoActivities(c.Request.Context(), dB, todoID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"activities": activities})
+	}
+}
+
+func listTodoHistory(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		events, err := todoController.TodoHistory(c.Request.Context(), dB, todoID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"events": events})
+	}
+}
+
+func restoreTodo(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, err := todoController.RestoreTodo(c.Request.Context(), dB, todoID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
+		c.JSON(http.StatusOK, todo)
+	}
+}
+
+func listTrash(
+	dB db.DB,
+	todoController controller.TodoController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		filter, err := webutils.FilterFromContext(c)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todos, err := todoController.Trash(c.Request.Context(), dB, filter)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, todos)
+	}
+}
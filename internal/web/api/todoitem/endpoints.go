@@ -0,0 +1,20 @@
+package todoitem
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AddAuthenticatedEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	todoItemController controller.TodoItemController,
+) {
+	r.POST("/todo/:id/items", middleware.RequireWriteScope(), createTodoItem(dB, todoItemController))
+	r.GET("/todo/:id/items", listTodoItems(dB, todoItemController))
+	r.PUT("/todo/:id/items", middleware.RequireWriteScope(), reorderTodoItems(dB, todoItemController))
+	r.POST("/todo/:id/items/:item_id", middleware.RequireWriteScope(), toggleTodoItem(dB, todoItemController))
+	r.DELETE("/todo/:id/items/:item_id", middleware.RequireWriteScope(), deleteTodoItem(dB, todoItemController))
+}
@@ -0,0 +1,173 @@
+package todoitem
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+func createTodoItem(
+	dB db.DB,
+	todoItemController controller.TodoItemController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.CreateTodoItemForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, todoItem, err := todoItemController.CreateTodoItem(c.Request.Context(), dB, todoID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
+
+		c.JSON(http.StatusCreated, todoItem)
+	}
+}
+
+func listTodoItems(
+	dB db.DB,
+	todoItemController controller.TodoItemController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todoItems, err := todoItemController.TodoItems(c.Request.Context(), dB, todoID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": todoItems})
+	}
+}
+
+func reorderTodoItems(
+	dB db.DB,
+	todoItemController controller.TodoItemController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.ReorderTodoItemsForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, todoItems, err := todoItemController.ReorderTodoItems(c.Request.Context(), dB, todoID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
+
+		c.JSON(http.StatusOK, gin.H{"items": todoItems})
+	}
+}
+
+func toggleTodoItem(
+	dB db.DB,
+	todoItemController controller.TodoItemController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todoItemID, err := strconv.ParseInt(c.Param("item_id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, todoItem, err := todoItemController.ToggleTodoItem(c.Request.Context(), dB, todoID, todoItemID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
+
+		c.JSON(http.StatusOK, todoItem)
+	}
+}
+
+func deleteTodoItem(
+	dB db.DB,
+	todoItemController controller.TodoItemController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		todoID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todoItemID, err := strconv.ParseInt(c.Param("item_id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		todo, err := todoItemController.DeleteTodoItem(c.Request.Context(), dB, todoID, todoItemID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.Header("ETag", todo.ETag())
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
@@ -0,0 +1,23 @@
+package workspace
+
+import (
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func AddAuthenticatedEndpoints(
+	r *gin.RouterGroup,
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) {
+	r.POST("/workspace", middleware.RequireWriteScope(), createWorkspace(dB, workspaceController))
+	r.GET("/workspaces", listWorkspaces(dB, workspaceController))
+	r.GET("/workspace/:id", workspaceByID(dB, workspaceController))
+	r.GET("/workspace/:id/members", listMembers(dB, workspaceController))
+	r.PUT("/workspace/:id/members/:user_id", middleware.RequireWriteScope(), updateMember(dB, workspaceController))
+	r.DELETE("/workspace/:id/members/:user_id", middleware.RequireWriteScope(), removeMember(dB, workspaceController))
+	r.POST("/workspace/:id/invitations", middleware.RequireWriteScope(), createInvitation(dB, workspaceController))
+	r.POST("/invitations/accept", middleware.RequireWriteScope(), acceptInvitation(dB, workspaceController))
+}
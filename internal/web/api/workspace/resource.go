@@ -0,0 +1,234 @@
+package workspace
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+func createWorkspace(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.CreateWorkspaceForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		workspace, err := workspaceController.CreateWorkspace(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusCreated, workspace)
+	}
+}
+
+func listWorkspaces(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		workspaces, err := workspaceController.Workspaces(c.Request.Context(), dB)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"workspaces": workspaces})
+	}
+}
+
+func workspaceByID(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		workspaceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		workspace, err := workspaceController.WorkspaceByID(c.Request.Context(), dB, workspaceID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, workspace)
+	}
+}
+
+func listMembers(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		workspaceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		members, err := workspaceController.Members(c.Request.Context(), dB, workspaceID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"members": members})
+	}
+}
+
+func updateMember(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		workspaceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.UpdateMemberForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		member, err := workspaceController.UpdateMember(c.Request.Context(), dB, workspaceID, userID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, member)
+	}
+}
+
+func removeMember(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		workspaceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		err = workspaceController.RemoveMember(c.Request.Context(), dB, workspaceID, userID)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"success": true})
+	}
+}
+
+func createInvitation(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		workspaceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		var form forms.CreateInvitationForm
+
+		err = c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		invitation, err := workspaceController.CreateInvitation(c.Request.Context(), dB, workspaceID, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusCreated, invitation)
+	}
+}
+
+func acceptInvitation(
+	dB db.DB,
+	workspaceController controller.WorkspaceController,
+) func(c *gin.Context) {
+	return func(c *gin.Context) {
+
+		var form forms.AcceptInvitationForm
+
+		err := c.BindJSON(&form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		member, err := workspaceController.AcceptInvitation(c.Request.Context(), dB, &form)
+		if err != nil {
+			appError := apperror.Wrap(err)
+			webutils.HandleError(c, appError)
+			return
+		}
+
+		c.JSON(http.StatusOK, member)
+	}
+}
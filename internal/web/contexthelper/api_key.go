@@ -0,0 +1,24 @@
+package contexthelper
+
+import (
+	"context"
+
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+func APIKey(ctx context.Context) *entities.APIKey {
+	existing := ctx.Value(entities.ContextKeyAPIKey)
+	if existing == nil {
+		return nil
+	}
+
+	if apiKey, ok := existing.(*entities.APIKey); ok {
+		return apiKey
+	}
+
+	return nil
+}
+
+func WithAPIKey(ctx context.Context, apiKey *entities.APIKey) context.Context {
+	return context.WithValue(ctx, entities.ContextKeyAPIKey, apiKey)
+}
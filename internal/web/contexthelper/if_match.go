@@ -0,0 +1,19 @@
+package contexthelper
+
+import (
+	"context"
+
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+func IfMatch(ctx context.Context) string {
+	existing := ctx.Value(entities.ContextKeyIfMatch)
+	if existing == nil {
+		return ""
+	}
+	return existing.(string)
+}
+
+func WithIfMatch(ctx context.Context, ifMatch string) context.Context {
+	return context.WithValue(ctx, entities.ContextKeyIfMatch, ifMatch)
+}
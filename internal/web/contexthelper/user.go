@@ -0,0 +1,24 @@
+package contexthelper
+
+import (
+	"context"
+
+	"github.com/ernestngugi/todo/internal/entities"
+)
+
+func User(ctx context.Context) *entities.User {
+	existing := ctx.Value(entities.ContextKeyUser)
+	if existing == nil {
+		return nil
+	}
+
+	if user, ok := existing.(*entities.User); ok {
+		return user
+	}
+
+	return nil
+}
+
+func WithUser(ctx context.Context, user *entities.User) context.Context {
+	return context.WithValue(ctx, entities.ContextKeyUser, user)
+}
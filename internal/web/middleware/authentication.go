@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	"github.com/ernestngugi/todo/internal/web/webutils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APIKeyHeaderKey        = "X-API-Key"
+	authorizationHeaderKey = "Authorization"
+	bearerPrefix           = "bearer "
+)
+
+func AuthenticationMiddleware(
+	dB db.DB,
+	authController controller.AuthController,
+	apiKeyController controller.APIKeyController,
+) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		ctx := c.Request.Context()
+
+		key := strings.TrimSpace(c.Request.Header.Get(APIKeyHeaderKey))
+
+		if key == "" {
+
+			token, err := bearerToken(c)
+			if err != nil {
+				webutils.HandleError(c, apperror.Wrap(err).SetHttpStatusCode(http.StatusUnauthorized))
+				c.Abort()
+				return
+			}
+
+			if !strings.HasPrefix(token, controller.APIKeyPrefix) {
+
+				user, err := authController.AuthenticateToken(ctx, dB, token)
+				if err != nil {
+					webutils.HandleError(c, apperror.Wrap(err))
+					c.Abort()
+					return
+				}
+
+				c.Request = c.Request.WithContext(contexthelper.WithUser(ctx, user))
+				c.Next()
+				return
+			}
+
+			key = token
+		}
+
+		user, apiKey, err := apiKeyController.AuthenticateAPIKey(ctx, dB, key)
+		if err != nil {
+			webutils.HandleError(c, apperror.Wrap(err))
+			c.Abort()
+			return
+		}
+
+		ctx = contexthelper.WithUser(ctx, user)
+		ctx = contexthelper.WithAPIKey(ctx, apiKey)
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func RequireWriteScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		apiKey := contexthelper.APIKey(c.Request.Context())
+		if apiKey != nil && !apiKey.Scope.CanWrite() {
+			webutils.HandleError(c, apperror.Wrap(errors.New("api key does not have write access")).SetHttpStatusCode(http.StatusForbidden))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func bearerToken(c *gin.Context) (string, error) {
+
+	header := strings.TrimSpace(c.Request.Header.Get(authorizationHeaderKey))
+	if header == "" {
+		return "", errors.New("missing authorization header")
+	}
+
+	if len(header) <= len(bearerPrefix) || strings.ToLower(header[:len(bearerPrefix)]) != bearerPrefix {
+		return "", errors.New("invalid authorization header")
+	}
+
+	return strings.TrimSpace(header[len(bearerPrefix):]), nil
+}
@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ernestngugi/todo/internal/apperror"
+	"github.com/ernestngugi/todo/internal/controller"
+	"github.com/ernestngugi/todo/internal/db"
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/web/contexthelper"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testAuthController struct {
+	controller.AuthController
+	token string
+	user  *entities.User
+}
+
+func (s *testAuthController) AuthenticateToken(ctx context.Context, dB db.DB, token string) (*entities.User, error) {
+	if token != s.token {
+		return &entities.User{}, apperror.Wrap(errors.New("invalid token")).SetHttpStatusCode(http.StatusUnauthorized)
+	}
+	return s.user, nil
+}
+
+type testAPIKeyController struct {
+	controller.APIKeyController
+	apiKeys map[string]*entities.APIKey
+	user    *entities.User
+}
+
+func (s *testAPIKeyController) AuthenticateAPIKey(ctx context.Context, dB db.DB, key string) (*entities.User, *entities.APIKey, error) {
+	apiKey, ok := s.apiKeys[key]
+	if !ok {
+		return &entities.User{}, &entities.APIKey{}, apperror.Wrap(errors.New("invalid api key")).SetHttpStatusCode(http.StatusUnauthorized)
+	}
+	return s.user, apiKey, nil
+}
+
+func TestAuthenticationMiddleware(t *testing.T) {
+
+	Convey("TestAuthenticationMiddleware", t, func() {
+
+		user := &entities.User{
+			Identifier: entities.Identifier{ID: 1},
+			Email:      "test@example.com",
+		}
+
+		authController := &testAuthController{
+			token: "valid-token",
+			user:  user,
+		}
+
+		apiKeyController := &testAPIKeyController{
+			apiKeys: map[string]*entities.APIKey{
+				"todo_read":       {Scope: entities.APIKeyScopeRead},
+				"todo_read_write": {Scope: entities.APIKeyScopeReadWrite},
+			},
+			user: user,
+		}
+
+		testRouter := gin.Default()
+		testRouter.Use(DefaultMiddlewares()...)
+		testRouter.Use(AuthenticationMiddleware(nil, authController, apiKeyController))
+
+		testRouter.GET("/me", func(c *gin.Context) {
+			c.JSON(http.StatusOK, contexthelper.User(c.Request.Context()))
+		})
+
+		testRouter.POST("/me", RequireWriteScope(), func(c *gin.Context) {
+			c.JSON(http.StatusOK, contexthelper.User(c.Request.Context()))
+		})
+
+		doRequestWithHeader := func(method, header, value string) *httptest.ResponseRecorder {
+			w := httptest.NewRecorder()
+
+			req, err := http.NewRequest(method, "/me", nil)
+			So(err, ShouldBeNil)
+
+			if value != "" {
+				req.Header.Set(header, value)
+			}
+
+			testRouter.ServeHTTP(w, req)
+			return w
+		}
+
+		doRequest := func(authorization string) *httptest.ResponseRecorder {
+			return doRequestWithHeader(http.MethodGet, "Authorization", authorization)
+		}
+
+		Convey("rejects requests without an authorization header", func() {
+
+			w := doRequest("")
+
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("rejects requests with a malformed authorization header", func() {
+
+			w := doRequest("Basic dXNlcjpwYXNz")
+
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("rejects requests with an invalid token", func() {
+
+			w := doRequest("Bearer invalid")
+
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("sets the authenticated user in the context", func() {
+
+			w := doRequest("Bearer valid-token")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldContainSubstring, user.Email)
+		})
+
+		Convey("authenticates an api key in the X-API-Key header", func() {
+
+			w := doRequestWithHeader(http.MethodGet, "X-API-Key", "todo_read")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+			So(w.Body.String(), ShouldContainSubstring, user.Email)
+		})
+
+		Convey("authenticates an api key passed as a bearer token", func() {
+
+			w := doRequest("Bearer todo_read")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("rejects an unknown api key", func() {
+
+			w := doRequestWithHeader(http.MethodGet, "X-API-Key", "todo_unknown")
+
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("rejects writes with a read only api key", func() {
+
+			w := doRequestWithHeader(http.MethodPost, "X-API-Key", "todo_read")
+
+			So(w.Code, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("allows writes with a read write api key", func() {
+
+			w := doRequestWithHeader(http.MethodPost, "X-API-Key", "todo_read_write")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("allows writes with an access token", func() {
+
+			w := doRequestWithHeader(http.MethodPost, "Authorization", "Bearer valid-token")
+
+			So(w.Code, ShouldEqual, http.StatusOK)
+		})
+	})
+}
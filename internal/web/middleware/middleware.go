@@ -15,6 +15,7 @@ import (
 )
 
 const (
+	ifMatchHeaderKey   = "If-Match"
 	RequestIdHeaderKey = "X-Request-ID"
 	userAgentHeaderKey = "user-agent"
 )
@@ -41,6 +42,9 @@ func setupContextMiddleware() gin.HandlerFunc {
 		userAgent := c.Request.Header.Get(userAgentHeaderKey)
 		ctx = contexthelper.WithUserAgent(ctx, userAgent)
 
+		ifMatch := c.Request.Header.Get(ifMatchHeaderKey)
+		ctx = contexthelper.WithIfMatch(ctx, ifMatch)
+
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	}
@@ -52,8 +56,8 @@ func corsMiddleware() gin.HandlerFunc {
 		c.Writer.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token, Authorization, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-Timezone, If-Match, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "X-CSRF-Token, Authorization, X-Requested-With, ETag")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
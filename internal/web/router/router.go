@@ -8,7 +8,16 @@ import (
 	"github.com/ernestngugi/todo/internal/db"
 	"github.com/ernestngugi/todo/internal/providers"
 	"github.com/ernestngugi/todo/internal/repository"
+	"github.com/ernestngugi/todo/internal/utils"
+	"github.com/ernestngugi/todo/internal/web/api/apikey"
+	"github.com/ernestngugi/todo/internal/web/api/attachment"
+	"github.com/ernestngugi/todo/internal/web/api/auth"
+	"github.com/ernestngugi/todo/internal/web/api/comment"
+	"github.com/ernestngugi/todo/internal/web/api/project"
+	"github.com/ernestngugi/todo/internal/web/api/tag"
 	"github.com/ernestngugi/todo/internal/web/api/todo"
+	"github.com/ernestngugi/todo/internal/web/api/todoitem"
+	"github.com/ernestngugi/todo/internal/web/api/workspace"
 	"github.com/ernestngugi/todo/internal/web/middleware"
 	"github.com/gin-gonic/gin"
 )
@@ -19,7 +28,10 @@ type AppRouter struct {
 
 func BuildRouter(
 	dB db.DB,
-	redisManager providers.Redis,
+	blobStore providers.BlobStore,
+	cacheConfig *controller.CacheConfig,
+	jwtProvider providers.JWT,
+	cacheBackend providers.Redis,
 ) *AppRouter {
 
 	if os.Getenv("ENVIRONMENT") == "development" {
@@ -33,13 +45,49 @@ func BuildRouter(
 
 	appRouter := router.Group("/v1")
 
+	apiKeyRepository := repository.NewAPIKeyRepository()
+	attachmentRepository := repository.NewAttachmentRepository()
+	commentRepository := repository.NewCommentRepository()
+	projectRepository := repository.NewProjectRepository()
+	refreshTokenRepository := repository.NewRefreshTokenRepository()
+	tagRepository := repository.NewTagRepository()
+	todoActivityRepository := repository.NewTodoActivityRepository()
+	todoEventRepository := repository.NewTodoEventRepository()
+	todoItemRepository := repository.NewTodoItemRepository()
 	todoRepository := repository.NewTodoRepository()
+	userRepository := repository.NewUserRepository()
+	workspaceInvitationRepository := repository.NewWorkspaceInvitationRepository()
+	workspaceRepository := repository.NewWorkspaceRepository()
 
-	cacheController := controller.NewCacheController(redisManager)
+	accessController := controller.NewAccessController(workspaceRepository)
 
-	todoController := controller.NewTodoController(cacheController, todoRepository)
+	apiKeyController := controller.NewAPIKeyController(apiKeyRepository, userRepository)
+	authController := controller.NewAuthController(jwtProvider, refreshTokenRepository, userRepository)
+	cacheController := controller.NewCacheController(cacheConfig, cacheBackend)
 
-	todo.AddOpenEndpoints(appRouter, dB, todoController)
+	cursorCodec := utils.NewCursorCodec(os.Getenv("CURSOR_SECRET"))
+
+	tagController := controller.NewTagController(cacheController, tagRepository, todoRepository)
+	todoController := controller.NewTodoController(accessController, cacheController, cursorCodec, projectRepository, tagRepository, todoActivityRepository, todoEventRepository, todoItemRepository, todoRepository, workspaceRepository)
+	todoItemController := controller.NewTodoItemController(accessController, cacheController, todoItemRepository, todoRepository)
+	projectController := controller.NewProjectController(cacheController, projectRepository, todoController)
+	attachmentController := controller.NewAttachmentController(accessController, attachmentRepository, blobStore, todoRepository)
+	commentController := controller.NewCommentController(accessController, commentRepository, todoRepository)
+	workspaceController := controller.NewWorkspaceController(accessController, workspaceInvitationRepository, workspaceRepository)
+
+	auth.AddOpenEndpoints(appRouter, dB, authController)
+
+	authenticatedRouter := appRouter.Group("")
+	authenticatedRouter.Use(middleware.AuthenticationMiddleware(dB, authController, apiKeyController))
+
+	apikey.AddAuthenticatedEndpoints(authenticatedRouter, dB, apiKeyController)
+	attachment.AddAuthenticatedEndpoints(authenticatedRouter, dB, attachmentController)
+	comment.AddAuthenticatedEndpoints(authenticatedRouter, dB, commentController)
+	project.AddAuthenticatedEndpoints(authenticatedRouter, dB, projectController)
+	tag.AddAuthenticatedEndpoints(authenticatedRouter, dB, tagController)
+	todo.AddAuthenticatedEndpoints(authenticatedRouter, dB, todoController)
+	todoitem.AddAuthenticatedEndpoints(authenticatedRouter, dB, todoItemController)
+	workspace.AddAuthenticatedEndpoints(authenticatedRouter, dB, workspaceController)
 
 	router.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error_message": "Endpoint not found"})
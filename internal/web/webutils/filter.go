@@ -2,20 +2,28 @@ package webutils
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ernestngugi/todo/internal/entities"
 	"github.com/ernestngugi/todo/internal/forms"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultCursorLimit = 20
+	maxCursorLimit     = 100
+)
+
 func FilterFromContext(
 	c *gin.Context,
 ) (*forms.Filter, error) {
 
 	filter := &forms.Filter{}
 
-	page, per, err := paginationFromContext(c)
+	page, per, err := PaginationFromContext(c)
 	if err != nil {
 		return filter, err
 	}
@@ -23,20 +31,330 @@ func FilterFromContext(
 	filter.Page = page
 	filter.Per = per
 
-	isValid := strings.TrimSpace(c.Query("valid"))
-	if isValid != "" {
-		isValid, err := strconv.ParseBool(isValid)
+	filter.Limit, filter.CursorToken, err = cursorFromContext(c)
+	if err != nil {
+		return filter, err
+	}
+
+	completed := strings.TrimSpace(c.Query("completed"))
+	if completed != "" {
+		completed, err := strconv.ParseBool(completed)
 		if err != nil {
-			return filter, fmt.Errorf("invalid valid argument %v", err)
+			return filter, fmt.Errorf("invalid completed argument %v", err)
 		}
 
-		filter.Valid = &isValid
+		filter.Completed = &completed
+	}
+
+	filter.Term = strings.TrimSpace(c.Query("q"))
+
+	filter.CreatedAfter, filter.CreatedBefore, err = timeRangeFromContext(c, "created_after", "created_before")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.CompletedAfter, filter.CompletedBefore, err = timeRangeFromContext(c, "completed_after", "completed_before")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.DueAfter, filter.DueBefore, err = timeRangeFromContext(c, "due_after", "due_before")
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Priorities, err = prioritiesFromContext(c)
+	if err != nil {
+		return filter, err
+	}
+
+	filter.Statuses, err = statusesFromContext(c)
+	if err != nil {
+		return filter, err
+	}
+
+	projectID := strings.TrimSpace(c.Query("project_id"))
+	if projectID != "" {
+		filter.ProjectID, err = strconv.ParseInt(projectID, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid project_id argument %v", err)
+		}
+	}
+
+	includeArchived := strings.TrimSpace(c.Query("include_archived"))
+	if includeArchived != "" {
+		filter.IncludeArchived, err = strconv.ParseBool(includeArchived)
+		if err != nil {
+			return filter, fmt.Errorf("invalid include_archived argument %v", err)
+		}
+	}
+
+	seriesID := strings.TrimSpace(c.Query("series_id"))
+	if seriesID != "" {
+		filter.SeriesID, err = strconv.ParseInt(seriesID, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid series_id argument %v", err)
+		}
+	}
+
+	filter.AssignedToMe, filter.AssigneeID, filter.Unassigned, err = assigneeFromContext(c)
+	if err != nil {
+		return filter, err
+	}
+
+	workspaceID := strings.TrimSpace(c.Query("workspace_id"))
+	if workspaceID != "" {
+		filter.WorkspaceID, err = strconv.ParseInt(workspaceID, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("invalid workspace_id argument %v", err)
+		}
+	}
+
+	filter.Tags, filter.TagMatch, err = tagsFromContext(c)
+	if err != nil {
+		return filter, err
+	}
+
+	overdue := strings.TrimSpace(c.Query("overdue"))
+	if overdue != "" {
+		filter.Overdue, err = strconv.ParseBool(overdue)
+		if err != nil {
+			return filter, fmt.Errorf("invalid overdue argument %v", err)
+		}
+	}
+
+	filter.Sort, err = sortFromContext(c, forms.TodoSortFields)
+	if err != nil {
+		return filter, err
 	}
 
 	return filter, nil
 }
 
-func paginationFromContext(
+func prioritiesFromContext(
+	c *gin.Context,
+) ([]entities.Priority, error) {
+
+	priorities := make([]entities.Priority, 0)
+
+	for _, value := range c.QueryArray("priority") {
+		for _, name := range strings.Split(value, ",") {
+
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			priority, err := entities.ParsePriority(name)
+			if err != nil {
+				return priorities, err
+			}
+
+			if !slices.Contains(priorities, priority) {
+				priorities = append(priorities, priority)
+			}
+		}
+	}
+
+	return priorities, nil
+}
+
+func statusesFromContext(
+	c *gin.Context,
+) ([]entities.TodoStatus, error) {
+
+	statuses := make([]entities.TodoStatus, 0)
+
+	for _, value := range c.QueryArray("status") {
+		for _, name := range strings.Split(value, ",") {
+
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+
+			status, err := entities.ParseTodoStatus(name)
+			if err != nil {
+				return statuses, err
+			}
+
+			if !slices.Contains(statuses, status) {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+
+	return statuses, nil
+}
+
+// assigneeFromContext reads assignee=me, assignee=none or assignee=<user id>.
+func assigneeFromContext(
+	c *gin.Context,
+) (bool, int64, bool, error) {
+
+	assignee := strings.ToLower(strings.TrimSpace(c.Query("assignee")))
+
+	switch assignee {
+	case "":
+		return false, 0, false, nil
+	case "me":
+		return true, 0, false, nil
+	case "none":
+		return false, 0, true, nil
+	}
+
+	assigneeID, err := strconv.ParseInt(assignee, 10, 64)
+	if err != nil || assigneeID < 1 {
+		return false, 0, false, fmt.Errorf("invalid assignee argument %v, expected me, none or a user id", assignee)
+	}
+
+	return false, assigneeID, false, nil
+}
+
+func tagsFromContext(
+	c *gin.Context,
+) ([]string, string, error) {
+
+	tags := make([]string, 0)
+
+	for _, value := range c.QueryArray("tag") {
+		for _, name := range strings.Split(value, ",") {
+
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+
+			if !slices.Contains(tags, name) {
+				tags = append(tags, name)
+			}
+		}
+	}
+
+	tagMatch := strings.ToLower(strings.TrimSpace(c.Query("tag_match")))
+
+	switch tagMatch {
+	case "":
+		tagMatch = forms.TagMatchAny
+	case forms.TagMatchAll, forms.TagMatchAny:
+	default:
+		return tags, tagMatch, fmt.Errorf("invalid tag_match argument %v, expected %v or %v", tagMatch, forms.TagMatchAny, forms.TagMatchAll)
+	}
+
+	return tags, tagMatch, nil
+}
+
+func sortFromContext(
+	c *gin.Context,
+	allowedFields []string,
+) ([]forms.SortField, error) {
+
+	sortFields := make([]forms.SortField, 0)
+	seen := make(map[string]bool)
+
+	for _, value := range c.QueryArray("sort") {
+		for _, field := range strings.Split(value, ",") {
+
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+
+			sortField := forms.SortField{
+				Descending: strings.HasPrefix(field, "-"),
+				Field:      strings.TrimPrefix(field, "-"),
+			}
+
+			if !slices.Contains(allowedFields, sortField.Field) {
+				return sortFields, fmt.Errorf("invalid sort field %v, allowed fields are %v", sortField.Field, strings.Join(allowedFields, ", "))
+			}
+
+			if seen[sortField.Field] {
+				return sortFields, fmt.Errorf("duplicate sort field %v", sortField.Field)
+			}
+
+			seen[sortField.Field] = true
+			sortFields = append(sortFields, sortField)
+		}
+	}
+
+	return sortFields, nil
+}
+
+func timeRangeFromContext(
+	c *gin.Context,
+	afterKey string,
+	beforeKey string,
+) (*time.Time, *time.Time, error) {
+
+	after, err := timeFromContext(c, afterKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	before, err := timeFromContext(c, beforeKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if after != nil && before != nil && !after.Before(*before) {
+		return nil, nil, fmt.Errorf("%v must be before %v", afterKey, beforeKey)
+	}
+
+	return after, before, nil
+}
+
+func timeFromContext(
+	c *gin.Context,
+	key string,
+) (*time.Time, error) {
+
+	value := strings.TrimSpace(c.Query(key))
+	if value == "" {
+		return nil, nil
+	}
+
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return &t, nil
+		}
+	}
+
+	return nil, fmt.Errorf("invalid %v argument, expected RFC3339 timestamp or YYYY-MM-DD date", key)
+}
+
+func cursorFromContext(
+	c *gin.Context,
+) (int, string, error) {
+
+	cursor := strings.TrimSpace(c.Query("cursor"))
+	limitQuery := strings.TrimSpace(c.Query("limit"))
+
+	if cursor == "" && limitQuery == "" {
+		return 0, "", nil
+	}
+
+	limit := defaultCursorLimit
+
+	if limitQuery != "" {
+
+		var err error
+
+		limit, err = strconv.Atoi(limitQuery)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid limit argument %v", err)
+		}
+
+		if limit < 1 || limit > maxCursorLimit {
+			return 0, "", fmt.Errorf("limit must be between 1 and %v", maxCursorLimit)
+		}
+	}
+
+	return limit, cursor, nil
+}
+
+func PaginationFromContext(
 	c *gin.Context,
 ) (int, int, error) {
 
@@ -0,0 +1,221 @@
+package webutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ernestngugi/todo/internal/entities"
+	"github.com/ernestngugi/todo/internal/forms"
+	"github.com/gin-gonic/gin"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFilterFromContext(t *testing.T) {
+
+	Convey("TestFilterFromContext", t, func() {
+
+		contextWithQuery := func(query string) *gin.Context {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodGet, "/todos?"+query, nil)
+			return c
+		}
+
+		Convey("defaults pagination", func() {
+
+			filter, err := FilterFromContext(contextWithQuery(""))
+			So(err, ShouldBeNil)
+
+			So(filter.Page, ShouldEqual, 1)
+			So(filter.Per, ShouldEqual, 20)
+			So(filter.Completed, ShouldBeNil)
+		})
+
+		Convey("parses completion and search filters", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("completed=true&q=%20milk%20"))
+			So(err, ShouldBeNil)
+
+			So(*filter.Completed, ShouldBeTrue)
+			So(filter.Term, ShouldEqual, "milk")
+		})
+
+		Convey("parses date ranges", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("created_after=2024-01-01&created_before=2024-02-01T10:00:00Z"))
+			So(err, ShouldBeNil)
+
+			So(filter.CreatedAfter.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+			So(filter.CreatedBefore.Equal(time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC)), ShouldBeTrue)
+		})
+
+		Convey("rejects an invalid completed argument", func() {
+
+			_, err := FilterFromContext(contextWithQuery("completed=maybe"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("rejects an inverted date range", func() {
+
+			_, err := FilterFromContext(contextWithQuery("completed_after=2024-02-01&completed_before=2024-01-01"))
+			So(err, ShouldNotBeNil)
+
+			So(err.Error(), ShouldEqual, "completed_after must be before completed_before")
+		})
+
+		Convey("rejects an invalid date", func() {
+
+			_, err := FilterFromContext(contextWithQuery("created_after=yesterday"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("parses sort fields", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("sort=-created_at,title"))
+			So(err, ShouldBeNil)
+
+			So(filter.Sort, ShouldResemble, []forms.SortField{
+				{Field: forms.SortFieldCreatedAt, Descending: true},
+				{Field: forms.SortFieldTitle},
+			})
+		})
+
+		Convey("rejects unknown sort fields", func() {
+
+			_, err := FilterFromContext(contextWithQuery("sort=password"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("rejects duplicate sort fields", func() {
+
+			_, err := FilterFromContext(contextWithQuery("sort=title,-title"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("parses cursor pagination", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("cursor=abc.def&limit=5"))
+			So(err, ShouldBeNil)
+
+			So(filter.CursorToken, ShouldEqual, "abc.def")
+			So(filter.Limit, ShouldEqual, 5)
+
+			filter, err = FilterFromContext(contextWithQuery("limit=5"))
+			So(err, ShouldBeNil)
+
+			So(filter.CursorToken, ShouldBeEmpty)
+			So(filter.Limit, ShouldEqual, 5)
+		})
+
+		Convey("rejects an out of range limit", func() {
+
+			_, err := FilterFromContext(contextWithQuery("limit=1000"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("parses due date filters", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("overdue=true&due_before=2024-01-02"))
+			So(err, ShouldBeNil)
+
+			So(filter.Overdue, ShouldBeTrue)
+			So(filter.DueAfter, ShouldBeNil)
+			So(filter.DueBefore.Equal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)), ShouldBeTrue)
+		})
+
+		Convey("resolves the caller's time zone", func() {
+
+			location, err := LocationFromContext(contextWithQuery("tz=Africa/Nairobi"))
+			So(err, ShouldBeNil)
+
+			So(location.String(), ShouldEqual, "Africa/Nairobi")
+
+			c := contextWithQuery("")
+			c.Request.Header.Set("X-Timezone", "Europe/Berlin")
+
+			location, err = LocationFromContext(c)
+			So(err, ShouldBeNil)
+
+			So(location.String(), ShouldEqual, "Europe/Berlin")
+
+			location, err = LocationFromContext(contextWithQuery(""))
+			So(err, ShouldBeNil)
+
+			So(location, ShouldEqual, time.UTC)
+		})
+
+		Convey("rejects an unknown time zone", func() {
+
+			_, err := LocationFromContext(contextWithQuery("tz=Mars/Olympus"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("parses priority filters", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("priority=high,urgent&priority=high"))
+			So(err, ShouldBeNil)
+
+			So(filter.Priorities, ShouldResemble, []entities.Priority{entities.PriorityHigh, entities.PriorityUrgent})
+		})
+
+		Convey("rejects an unknown priority", func() {
+
+			_, err := FilterFromContext(contextWithQuery("priority=critical"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("parses status filters", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("status=open,in_progress&status=open"))
+			So(err, ShouldBeNil)
+
+			So(filter.Statuses, ShouldResemble, []entities.TodoStatus{entities.TodoStatusOpen, entities.TodoStatusInProgress})
+		})
+
+		Convey("rejects an unknown status", func() {
+
+			_, err := FilterFromContext(contextWithQuery("status=waiting"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("parses tag filters", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("tag=Work,urgent&tag=work&tag_match=all"))
+			So(err, ShouldBeNil)
+
+			So(filter.Tags, ShouldResemble, []string{"work", "urgent"})
+			So(filter.TagMatch, ShouldEqual, forms.TagMatchAll)
+		})
+
+		Convey("rejects an unknown tag match", func() {
+
+			_, err := FilterFromContext(contextWithQuery("tag=work&tag_match=some"))
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("parses assignee filters", func() {
+
+			filter, err := FilterFromContext(contextWithQuery("assignee=me"))
+			So(err, ShouldBeNil)
+
+			So(filter.AssignedToMe, ShouldBeTrue)
+
+			filter, err = FilterFromContext(contextWithQuery("assignee=none"))
+			So(err, ShouldBeNil)
+
+			So(filter.Unassigned, ShouldBeTrue)
+
+			filter, err = FilterFromContext(contextWithQuery("assignee=42"))
+			So(err, ShouldBeNil)
+
+			So(filter.AssigneeID, ShouldEqual, 42)
+		})
+
+		Convey("rejects an invalid assignee", func() {
+
+			_, err := FilterFromContext(contextWithQuery("assignee=someone"))
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
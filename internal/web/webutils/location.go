@@ -0,0 +1,34 @@
+package webutils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const timezoneHeaderKey = "X-Timezone"
+
+// LocationFromContext resolves the caller's IANA time zone from the tz query
+// argument or the X-Timezone header, defaulting to UTC.
+func LocationFromContext(
+	c *gin.Context,
+) (*time.Location, error) {
+
+	timezone := strings.TrimSpace(c.Query("tz"))
+	if timezone == "" {
+		timezone = strings.TrimSpace(c.GetHeader(timezoneHeaderKey))
+	}
+
+	if timezone == "" {
+		return time.UTC, nil
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC, fmt.Errorf("invalid time zone %v", timezone)
+	}
+
+	return location, nil
+}